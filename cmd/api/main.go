@@ -14,12 +14,12 @@ import (
 
 	"github.com/tomimandalaputra/e-commerce-go/internal/config"
 	"github.com/tomimandalaputra/e-commerce-go/internal/database"
-	"github.com/tomimandalaputra/e-commerce-go/internal/events"
 	"github.com/tomimandalaputra/e-commerce-go/internal/interfaces"
 	"github.com/tomimandalaputra/e-commerce-go/internal/logger"
 	"github.com/tomimandalaputra/e-commerce-go/internal/providers"
 	"github.com/tomimandalaputra/e-commerce-go/internal/server"
 	"github.com/tomimandalaputra/e-commerce-go/internal/services"
+	"github.com/tomimandalaputra/e-commerce-go/internal/utils"
 )
 
 // @title E-Commerce API
@@ -66,19 +66,36 @@ func main() {
 	}()
 
 	ctx := context.Background()
-	eventPublisher, err := events.NewEventPublisher(ctx, &cfg.AWS)
+
+	gin.SetMode(cfg.Server.GinMode)
+
+	keys, err := utils.NewKeySet(&cfg.JWT)
 	if err != nil {
-		log.Error().Err(err).Msg("failed to create event publisher")
-		return
+		log.Fatal().Err(err).Msg("Failed to load JWT keys")
 	}
 
-	gin.SetMode(cfg.Server.GinMode)
+	revocationService := services.NewTokenRevocationService(db, &cfg.Auth)
+	rbacService := services.NewRBACService(db, &cfg.Auth)
+	mfaService := services.NewMFAService(db, cfg, rbacService)
+	authService := services.NewAuthService(db, cfg, keys, revocationService, mfaService)
+	inventoryService := services.NewInventoryService(db, cfg.Inventory.ReservationTTL)
+	productService := services.NewProductService(db, inventoryService)
+	userService := services.NewUserService(db, revocationService)
+	cartService := services.NewCartService(db, inventoryService)
+
+	var paymentProvider interfaces.PaymentProvider
+	switch cfg.Payment.Provider {
+	case "fake":
+		paymentProvider = providers.NewFakePaymentProvider()
+	default:
+		log.Fatal().Str("provider", cfg.Payment.Provider).Msg("Unsupported payment provider")
+	}
 
-	authService := services.NewAuthService(db, cfg, eventPublisher)
-	productService := services.NewProductService(db)
-	userService := services.NewUserService(db)
-	cartService := services.NewCartService(db)
-	orderService := services.NewOrderService(db)
+	paymentService := services.NewPaymentService(paymentProvider, cfg.Payment.Currency)
+	orderService := services.NewOrderService(db, paymentService, inventoryService, cfg.Payment.AuthorizationWindow, cfg.Auth.RequireVerifiedEmail)
+	paymentWebhookService := services.NewPaymentWebhookService(db, paymentProvider, cfg.Payment.WebhookSecret, orderService)
+	idempotencyService := services.NewIdempotencyService(db, cfg.Server.IdempotencyKeyTTL)
+	webhookService := services.NewWebhookService(db, &cfg.Webhook, &http.Client{Timeout: cfg.Webhook.Timeout})
 
 	var uploadProvider interfaces.UploadProvider
 	if cfg.Upload.UploadProvider == "s3" {
@@ -99,10 +116,24 @@ func main() {
 		uploadService,
 		cartService,
 		orderService,
+		inventoryService,
+		paymentWebhookService,
+		idempotencyService,
+		webhookService,
+		revocationService,
+		mfaService,
+		rbacService,
 	)
 
 	router := srv.SetupRoutes()
 
+	sweeperCtx, stopSweeper := context.WithCancel(ctx)
+	go inventoryService.StartReservationSweeper(sweeperCtx, cfg.Inventory.ReservationSweepInterval, &log)
+	go revocationService.StartPurger(sweeperCtx, cfg.Auth.RevocationPurgeInterval, &log)
+	go authService.StartLoginAttemptPurger(sweeperCtx, cfg.Auth.Login.AttemptPurgeInterval, &log)
+	go orderService.StartCheckoutSweeper(sweeperCtx, cfg.Payment.SweepInterval, &log)
+	go idempotencyService.StartPurger(sweeperCtx, cfg.Server.IdempotencyPurgeInterval, &log)
+
 	httpServer := &http.Server{
 		Addr:         fmt.Sprintf(":%s", cfg.Server.Port),
 		Handler:      router,
@@ -122,6 +153,8 @@ func main() {
 	<-quit
 
 	log.Info().Msg("Shutting down server")
+	stopSweeper()
+
 	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 	defer cancel()
 
@@ -0,0 +1,186 @@
+// Command worker relays the domain events written to the outbox to the event
+// queue, consumes them and dispatches them to the handlers registered for
+// their event type. It also delivers the resulting merchant webhooks.
+package main
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/tomimandalaputra/e-commerce-go/internal/config"
+	"github.com/tomimandalaputra/e-commerce-go/internal/database"
+	"github.com/tomimandalaputra/e-commerce-go/internal/events"
+	"github.com/tomimandalaputra/e-commerce-go/internal/events/schema"
+	"github.com/tomimandalaputra/e-commerce-go/internal/logger"
+	"github.com/tomimandalaputra/e-commerce-go/internal/notifications"
+	"github.com/tomimandalaputra/e-commerce-go/internal/services"
+)
+
+func main() {
+	log := logger.New()
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to load config")
+	}
+
+	db, err := database.New(&cfg.Database)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to connect to database")
+	}
+
+	mainDB, err := db.DB()
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to get database connection")
+	}
+
+	defer func() {
+		if err := mainDB.Close(); err != nil {
+			log.Error().Err(err).Msg("Failed to close database connection")
+		}
+	}()
+
+	webhookService := services.NewWebhookService(db, &cfg.Webhook, &http.Client{Timeout: cfg.Webhook.Timeout})
+
+	var mailer notifications.Mailer
+	switch cfg.Mail.Transport {
+	case "smtp":
+		mailer = notifications.NewSMTPMailer(cfg.Mail.SMTPHost, cfg.Mail.SMTPPort, cfg.Mail.SMTPUsername, cfg.Mail.SMTPPassword, cfg.Mail.From)
+	case "file":
+		mailer = notifications.NewFileMailer(cfg.Mail.FilePath, cfg.Mail.From)
+	default:
+		log.Fatal().Str("transport", cfg.Mail.Transport).Msg("Unsupported mail transport")
+	}
+
+	templates, err := notifications.LoadTemplates()
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to load email templates")
+	}
+
+	notificationService := services.NewNotificationService(db, mailer, templates, cfg.Server.FrontendURL, cfg.Auth.TokenEncryptionKey)
+
+	registry := events.NewRegistry(&log)
+	registerHandlers(registry, webhookService, notificationService, &log)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// The relay publishes to the same transport the worker consumes, so events
+	// are only marked published once they can reach the handlers registered above
+	var transport *events.Transport
+	switch cfg.Events.Publisher {
+	case "sqs":
+		transport, err = events.NewSQSTransport(ctx, &cfg.AWS)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Failed to create event transport")
+		}
+	case "gochannel":
+		log.Warn().Msg("Events are passed in process and are lost if the worker stops")
+		transport = events.NewGoChannelTransport(&cfg.AWS)
+	default:
+		log.Fatal().Str("publisher", cfg.Events.Publisher).Msg("Unsupported event publisher")
+	}
+
+	defer func() {
+		if err := transport.Publisher.Close(); err != nil {
+			log.Error().Err(err).Msg("Failed to close event publisher")
+		}
+	}()
+
+	worker, err := events.NewWorker(transport, &cfg.AWS, &cfg.Worker, registry)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to create event worker")
+	}
+
+	deliveryCtx, stopDeliveries := context.WithCancel(ctx)
+	go webhookService.StartDeliveryWorker(deliveryCtx, &log)
+
+	done := make(chan error, 1)
+	go func() {
+		log.Info().Str("queue", cfg.AWS.EventQueueName).Msg("Starting event worker")
+		done <- worker.Run(ctx)
+	}()
+
+	// Start relaying once the worker is subscribed, so events published to an
+	// in-process channel are not dropped before anyone listens
+	outboxRelay := events.NewOutboxRelay(db, transport.Publisher, &cfg.Outbox, &log)
+	relayCtx, stopRelay := context.WithCancel(ctx)
+	go func() {
+		select {
+		case <-worker.Running():
+		case <-relayCtx.Done():
+			return
+		}
+
+		go outboxRelay.StartPurger(relayCtx)
+		outboxRelay.Start(relayCtx)
+	}()
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+
+	select {
+	case <-quit:
+	case err := <-done:
+		if err != nil {
+			log.Fatal().Err(err).Msg("Event worker stopped unexpectedly")
+		}
+		return
+	}
+
+	log.Info().Msg("Shutting down event worker")
+	stopRelay()
+	stopDeliveries()
+
+	// Close waits for in-flight messages so nothing is left half processed
+	if err := worker.Close(); err != nil {
+		log.Error().Err(err).Msg("Failed to shutdown event worker")
+	}
+
+	select {
+	case <-done:
+	case <-time.After(15 * time.Second):
+		log.Warn().Msg("Timed out waiting for event worker to stop")
+	}
+}
+
+// registerHandlers wires every known event type to its handlers.
+func registerHandlers(registry *events.Registry, webhookService *services.WebhookService, notificationService *services.NotificationService, log *zerolog.Logger) {
+	for _, eventType := range schema.EventTypes {
+		registry.Register(eventType, logEvent(log))
+		registry.Register(eventType, webhookService.EnqueueDeliveries)
+	}
+
+	registry.Register(schema.UserRegistered, notificationService.HandleUserRegistered)
+	registry.Register(schema.UserVerificationRequested, notificationService.HandleVerificationRequested)
+	registry.Register(schema.PasswordResetRequested, notificationService.HandlePasswordResetRequested)
+	registry.Register(schema.UserPasswordReset, notificationService.HandlePasswordChanged)
+	registry.Register(schema.UserPasswordChanged, notificationService.HandlePasswordChanged)
+	registry.Register(schema.OrderCreated, notificationService.HandleOrderCreated)
+	registry.Register(schema.OrderStatusChanged, notificationService.HandleOrderStatusChanged)
+}
+
+// logEvent records every event that reaches the worker. Payloads of internal
+// events are left out because they carry secrets.
+func logEvent(log *zerolog.Logger) events.HandlerFunc {
+	return func(envelope *schema.Envelope) error {
+		entry := log.Info().
+			Str("event_id", envelope.EventID).
+			Str("event_type", envelope.EventType).
+			Int("schema_version", envelope.SchemaVersion).
+			Str("correlation_id", envelope.CorrelationID).
+			Str("aggregate_id", envelope.AggregateID)
+
+		if !schema.IsInternalEventType(envelope.EventType) {
+			entry = entry.RawJSON("payload", envelope.Payload)
+		}
+
+		entry.Msg("event received")
+		return nil
+	}
+}
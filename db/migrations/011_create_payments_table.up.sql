@@ -0,0 +1,20 @@
+CREATE TYPE payment_status AS ENUM ('pending', 'authorized', 'captured', 'declined', 'voided', 'refunded', 'failed');
+
+CREATE TABLE payments (
+    id SERIAL PRIMARY KEY,
+    order_id INTEGER NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    provider VARCHAR(50) NOT NULL,
+    provider_payment_id VARCHAR(255),
+    amount DECIMAL(10,2) NOT NULL,
+    currency VARCHAR(3) NOT NULL,
+    status payment_status DEFAULT 'pending',
+    failure_reason VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_payments_order_id ON payments(order_id);
+CREATE INDEX idx_payments_provider_payment_id ON payments(provider_payment_id);
+CREATE INDEX idx_payments_status ON payments(status);
+CREATE INDEX idx_payments_deleted_at ON payments(deleted_at);
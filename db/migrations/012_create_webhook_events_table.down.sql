@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS webhook_events;
+DROP TYPE IF EXISTS webhook_event_status;
\ No newline at end of file
@@ -0,0 +1,17 @@
+CREATE TYPE webhook_event_status AS ENUM ('received', 'processed', 'failed', 'ignored');
+
+CREATE TABLE webhook_events (
+    id SERIAL PRIMARY KEY,
+    provider VARCHAR(50) NOT NULL,
+    event_id VARCHAR(255) NOT NULL,
+    event_type VARCHAR(100),
+    payload JSONB NOT NULL,
+    status webhook_event_status DEFAULT 'received',
+    error TEXT,
+    processed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(provider, event_id)
+);
+
+CREATE INDEX idx_webhook_events_status ON webhook_events(status);
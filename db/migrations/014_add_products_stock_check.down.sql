@@ -0,0 +1 @@
+ALTER TABLE products DROP CONSTRAINT IF EXISTS chk_products_stock_non_negative;
\ No newline at end of file
@@ -0,0 +1,17 @@
+CREATE TYPE reservation_status AS ENUM ('active', 'consumed', 'released');
+
+CREATE TABLE stock_reservations (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    product_id INTEGER NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    order_id INTEGER REFERENCES orders(id) ON DELETE SET NULL,
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    status reservation_status DEFAULT 'active',
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_stock_reservations_product_status ON stock_reservations(product_id, status);
+CREATE INDEX idx_stock_reservations_user_status ON stock_reservations(user_id, status);
+CREATE INDEX idx_stock_reservations_expires_at ON stock_reservations(expires_at);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS inventory_movements;
+DROP TYPE IF EXISTS inventory_movement_reason;
\ No newline at end of file
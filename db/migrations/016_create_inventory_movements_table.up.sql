@@ -0,0 +1,17 @@
+CREATE TYPE inventory_movement_reason AS ENUM ('order', 'cancellation', 'manual_adjustment', 'return', 'import');
+
+CREATE TABLE inventory_movements (
+    id SERIAL PRIMARY KEY,
+    product_id INTEGER NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    delta INTEGER NOT NULL CHECK (delta <> 0),
+    stock_after INTEGER NOT NULL,
+    reason inventory_movement_reason NOT NULL,
+    reference_id VARCHAR(100),
+    actor_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    note TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_inventory_movements_product_id ON inventory_movements(product_id);
+CREATE INDEX idx_inventory_movements_reason ON inventory_movements(reason);
+CREATE INDEX idx_inventory_movements_created_at ON inventory_movements(created_at);
@@ -0,0 +1,2 @@
+ALTER TABLE products DROP CONSTRAINT IF EXISTS chk_products_low_stock_threshold_non_negative;
+ALTER TABLE products DROP COLUMN IF EXISTS low_stock_threshold;
\ No newline at end of file
@@ -0,0 +1,2 @@
+ALTER TABLE products ADD COLUMN low_stock_threshold INTEGER NOT NULL DEFAULT 5;
+ALTER TABLE products ADD CONSTRAINT chk_products_low_stock_threshold_non_negative CHECK (low_stock_threshold >= 0);
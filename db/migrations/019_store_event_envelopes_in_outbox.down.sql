@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_outbox_events_event_id;
+ALTER TABLE outbox_events ADD COLUMN metadata JSONB NOT NULL DEFAULT '{}';
+ALTER TABLE outbox_events RENAME COLUMN envelope TO payload;
+UPDATE outbox_events SET payload = payload->'payload';
+ALTER TABLE outbox_events DROP COLUMN IF EXISTS event_id;
\ No newline at end of file
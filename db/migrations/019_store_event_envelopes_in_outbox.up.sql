@@ -0,0 +1,18 @@
+ALTER TABLE outbox_events ADD COLUMN event_id UUID NOT NULL DEFAULT gen_random_uuid();
+ALTER TABLE outbox_events ALTER COLUMN event_id DROP DEFAULT;
+
+-- Wrap events written before envelopes existed so they can still be relayed
+UPDATE outbox_events SET payload = jsonb_build_object(
+    'event_id', event_id,
+    'event_type', event_type,
+    'schema_version', 1,
+    'occurred_at', created_at,
+    'correlation_id', gen_random_uuid(),
+    'aggregate_id', '',
+    'payload', payload
+);
+
+ALTER TABLE outbox_events RENAME COLUMN payload TO envelope;
+ALTER TABLE outbox_events DROP COLUMN metadata;
+
+CREATE UNIQUE INDEX idx_outbox_events_event_id ON outbox_events(event_id);
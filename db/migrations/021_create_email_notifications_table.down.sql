@@ -0,0 +1 @@
+DROP TABLE IF EXISTS email_notifications;
\ No newline at end of file
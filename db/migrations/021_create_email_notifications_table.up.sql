@@ -0,0 +1,11 @@
+CREATE TABLE email_notifications (
+    id BIGSERIAL PRIMARY KEY,
+    event_id UUID NOT NULL,
+    template VARCHAR(100) NOT NULL,
+    recipient VARCHAR(255) NOT NULL,
+    user_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    sent_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uq_email_notifications_event_template UNIQUE (event_id, template)
+);
+
+CREATE INDEX idx_email_notifications_user_id ON email_notifications(user_id);
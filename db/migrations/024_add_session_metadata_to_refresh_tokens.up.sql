@@ -0,0 +1,2 @@
+ALTER TABLE refresh_tokens ADD COLUMN user_agent VARCHAR(512);
+ALTER TABLE refresh_tokens ADD COLUMN ip_address VARCHAR(45);
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS revoked_access_tokens;
+
+DROP INDEX IF EXISTS idx_refresh_tokens_user_id_access_token_expires_at;
+ALTER TABLE refresh_tokens DROP COLUMN IF EXISTS access_token_expires_at;
+ALTER TABLE refresh_tokens DROP COLUMN IF EXISTS access_token_id;
\ No newline at end of file
@@ -0,0 +1,14 @@
+-- Remember which access token was issued with each refresh token so sessions can be revoked completely
+ALTER TABLE refresh_tokens ADD COLUMN access_token_id UUID;
+ALTER TABLE refresh_tokens ADD COLUMN access_token_expires_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX idx_refresh_tokens_user_id_access_token_expires_at ON refresh_tokens(user_id, access_token_expires_at);
+
+CREATE TABLE revoked_access_tokens (
+    token_id UUID PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_revoked_access_tokens_expires_at ON revoked_access_tokens(expires_at);
@@ -0,0 +1,69 @@
+CREATE TABLE roles (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(50) UNIQUE NOT NULL,
+    description VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE permissions (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) UNIQUE NOT NULL,
+    description VARCHAR(255) NOT NULL DEFAULT ''
+);
+
+CREATE TABLE role_permissions (
+    role_id INTEGER NOT NULL REFERENCES roles(id) ON DELETE CASCADE,
+    permission_id INTEGER NOT NULL REFERENCES permissions(id) ON DELETE CASCADE,
+    PRIMARY KEY (role_id, permission_id)
+);
+
+CREATE TABLE user_roles (
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role_id INTEGER NOT NULL REFERENCES roles(id) ON DELETE CASCADE,
+    assigned_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, role_id)
+);
+
+CREATE INDEX idx_user_roles_role_id ON user_roles(role_id);
+
+INSERT INTO permissions (name, description) VALUES
+    ('categories:write', 'Create, update and delete categories'),
+    ('products:write', 'Create, update and delete products and their images'),
+    ('inventory:read', 'View stock levels and inventory movements'),
+    ('inventory:write', 'Adjust stock levels'),
+    ('orders:read', 'View any customer''s orders'),
+    ('orders:update', 'Change the status of orders'),
+    ('users:update', 'Activate and deactivate user accounts'),
+    ('users:unlock', 'Lift login lockouts'),
+    ('webhooks:manage', 'Manage webhook subscriptions and deliveries'),
+    ('roles:manage', 'Assign and revoke roles');
+
+INSERT INTO roles (name, description) VALUES
+    ('admin', 'Full access to every staff endpoint'),
+    ('catalog_manager', 'Maintains categories, products and stock'),
+    ('order_fulfillment', 'Processes and ships orders'),
+    ('support', 'Helps customers with their orders and accounts');
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id FROM roles r CROSS JOIN permissions p WHERE r.name = 'admin';
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id FROM roles r JOIN permissions p ON p.name IN (
+    'categories:write', 'products:write', 'inventory:read', 'inventory:write'
+) WHERE r.name = 'catalog_manager';
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id FROM roles r JOIN permissions p ON p.name IN (
+    'orders:read', 'orders:update', 'inventory:read'
+) WHERE r.name = 'order_fulfillment';
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id FROM roles r JOIN permissions p ON p.name IN (
+    'orders:read', 'users:unlock'
+) WHERE r.name = 'support';
+
+-- Existing admins keep every power they had
+INSERT INTO user_roles (user_id, role_id)
+SELECT u.id, r.id FROM users u JOIN roles r ON r.name = 'admin' WHERE u.role = 'admin';
@@ -0,0 +1,15 @@
+UPDATE orders SET status = 'cancelled' WHERE status = 'pending_payment';
+UPDATE order_status_history SET from_status = 'pending' WHERE from_status = 'pending_payment';
+UPDATE order_status_history SET to_status = 'pending' WHERE to_status = 'pending_payment';
+
+ALTER TYPE order_status RENAME TO order_status_old;
+CREATE TYPE order_status AS ENUM ('pending', 'confirmed', 'shipped', 'delivered', 'cancelled');
+
+ALTER TABLE orders ALTER COLUMN status DROP DEFAULT;
+ALTER TABLE orders ALTER COLUMN status TYPE order_status USING status::text::order_status;
+ALTER TABLE orders ALTER COLUMN status SET DEFAULT 'pending';
+ALTER TABLE order_status_history
+    ALTER COLUMN from_status TYPE order_status USING from_status::text::order_status,
+    ALTER COLUMN to_status TYPE order_status USING to_status::text::order_status;
+
+DROP TYPE order_status_old;
\ No newline at end of file
@@ -0,0 +1,6 @@
+ALTER TABLE outbox_events ADD COLUMN dead_at TIMESTAMP WITH TIME ZONE;
+
+DROP INDEX IF EXISTS idx_outbox_events_pending;
+CREATE INDEX idx_outbox_events_pending ON outbox_events(next_attempt_at, id) WHERE published_at IS NULL AND dead_at IS NULL;
+CREATE INDEX idx_outbox_events_published_at ON outbox_events(published_at) WHERE published_at IS NOT NULL;
+CREATE INDEX idx_outbox_events_dead_at ON outbox_events(dead_at) WHERE dead_at IS NOT NULL;
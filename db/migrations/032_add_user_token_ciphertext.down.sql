@@ -0,0 +1 @@
+ALTER TABLE user_tokens DROP COLUMN IF EXISTS token_ciphertext;
\ No newline at end of file
@@ -0,0 +1,8 @@
+-- The raw token is kept encrypted until the worker has emailed it, instead of travelling in the event
+ALTER TABLE user_tokens ADD COLUMN token_ciphertext TEXT;
+
+-- Verification events used to carry the raw token; blank it in events that are no longer pending
+UPDATE outbox_events SET envelope = jsonb_set(envelope, '{payload,token}', '""')
+WHERE event_type = 'USER_VERIFICATION_REQUESTED'
+  AND envelope -> 'payload' ? 'token'
+  AND (published_at IS NOT NULL OR dead_at IS NOT NULL);
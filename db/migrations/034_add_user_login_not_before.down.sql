@@ -0,0 +1 @@
+ALTER TABLE users DROP COLUMN IF EXISTS login_not_before;
\ No newline at end of file
@@ -0,0 +1,2 @@
+-- Replaces sleeping in the request after a failed login: later attempts are refused until this time
+ALTER TABLE users ADD COLUMN login_not_before TIMESTAMP WITH TIME ZONE;
@@ -0,0 +1,5 @@
+CREATE TYPE user_role AS ENUM ('admin', 'customer');
+ALTER TABLE users ADD COLUMN role user_role DEFAULT 'customer';
+
+UPDATE users SET role = 'admin'
+WHERE id IN (SELECT ur.user_id FROM user_roles ur JOIN roles r ON r.id = ur.role_id WHERE r.name = 'admin');
\ No newline at end of file
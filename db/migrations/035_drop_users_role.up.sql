@@ -0,0 +1,7 @@
+-- Staff permissions come from user_roles; catch admins flagged after the RBAC migration before dropping the legacy column
+INSERT INTO user_roles (user_id, role_id)
+SELECT u.id, r.id FROM users u JOIN roles r ON r.name = 'admin' WHERE u.role = 'admin'
+ON CONFLICT DO NOTHING;
+
+ALTER TABLE users DROP COLUMN role;
+DROP TYPE user_role;
@@ -0,0 +1 @@
+ALTER TYPE payment_status ADD VALUE IF NOT EXISTS 'void_pending' AFTER 'authorized';
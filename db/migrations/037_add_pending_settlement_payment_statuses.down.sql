@@ -0,0 +1,11 @@
+UPDATE payments SET status = 'authorized' WHERE status = 'capture_pending';
+UPDATE payments SET status = 'captured' WHERE status = 'refund_pending';
+
+ALTER TYPE payment_status RENAME TO payment_status_old;
+CREATE TYPE payment_status AS ENUM ('pending', 'authorized', 'void_pending', 'captured', 'declined', 'voided', 'refunded', 'failed');
+
+ALTER TABLE payments ALTER COLUMN status DROP DEFAULT;
+ALTER TABLE payments ALTER COLUMN status TYPE payment_status USING status::text::payment_status;
+ALTER TABLE payments ALTER COLUMN status SET DEFAULT 'pending';
+
+DROP TYPE payment_status_old;
\ No newline at end of file
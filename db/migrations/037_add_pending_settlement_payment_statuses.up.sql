@@ -0,0 +1,2 @@
+ALTER TYPE payment_status ADD VALUE IF NOT EXISTS 'capture_pending' AFTER 'authorized';
+ALTER TYPE payment_status ADD VALUE IF NOT EXISTS 'refund_pending' AFTER 'captured';
@@ -24,43 +24,53 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/auth/login": {
-            "post": {
-                "description": "Authenticate user with email and password",
-                "consumes": [
-                    "application/json"
+        "/admin/inventory/low-stock": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "List active products whose stock is at or below their low-stock threshold, emptiest first (requires inventory:read)",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Authentication"
+                    "Admin"
                 ],
-                "summary": "User login",
+                "summary": "Get low-stock report",
                 "parameters": [
                     {
-                        "description": "User login credentials",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_dto.LoginRequest"
-                        }
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Items per page",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Login successful",
+                        "description": "Low-stock products retrieved successfully",
                         "schema": {
                             "allOf": [
                                 {
-                                    "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                                    "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.PaginatedResponse"
                                 },
                                 {
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_dto.AuthResponse"
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_dto.LowStockProductResponse"
+                                            }
                                         }
                                     }
                                 }
@@ -68,7 +78,19 @@ const docTemplate = `{
                         }
                     },
                     "401": {
-                        "description": "Invalid credentials",
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission inventory:read required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
                         "schema": {
                             "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
                         }
@@ -76,9 +98,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/auth/logout": {
-            "post": {
-                "description": "Invalidate refresh token and logout user",
+        "/admin/orders/{id}/status": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Move an order to a new status (requires orders:update). Only legal transitions are accepted: pending to confirmed or cancelled, confirmed to shipped or cancelled, shipped to delivered. Orders whose payment is still being authorized (pending_payment) can only be cancelled.",
                 "consumes": [
                     "application/json"
                 ],
@@ -86,29 +113,66 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Authentication"
+                    "Admin"
                 ],
-                "summary": "User logout",
+                "summary": "Update order status",
                 "parameters": [
                     {
-                        "description": "Refresh token to invalidate",
+                        "type": "integer",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New order status",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_dto.RefreshTokenRequest"
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_dto.UpdateOrderStatusRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Logout successful",
+                        "description": "Order status updated successfully",
                         "schema": {
-                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_dto.OrderResponse"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "400": {
-                        "description": "Invalid request data",
+                        "description": "Invalid request data or illegal status transition",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission orders:update required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Order not found",
                         "schema": {
                             "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
                         }
@@ -116,51 +180,86 @@ const docTemplate = `{
                 }
             }
         },
-        "/auth/refresh": {
-            "post": {
-                "description": "Get a new access token using refresh token",
-                "consumes": [
-                    "application/json"
+        "/admin/products/{id}/inventory": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Retrieve the paginated stock ledger of a product, newest first (requires inventory:read)",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Authentication"
+                    "Admin"
                 ],
-                "summary": "Refresh access token",
+                "summary": "Get product inventory movements",
                 "parameters": [
                     {
-                        "description": "Refresh token",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_dto.RefreshTokenRequest"
-                        }
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Items per page",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Token refreshed successfully",
+                        "description": "Inventory movements retrieved successfully",
                         "schema": {
                             "allOf": [
                                 {
-                                    "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                                    "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.PaginatedResponse"
                                 },
                                 {
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_dto.AuthResponse"
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_dto.InventoryMovementResponse"
+                                            }
                                         }
                                     }
                                 }
                             ]
                         }
                     },
+                    "400": {
+                        "description": "Invalid product ID",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    },
                     "401": {
-                        "description": "Invalid refresh token",
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission inventory:read required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Product not found",
                         "schema": {
                             "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
                         }
@@ -168,9 +267,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/auth/register": {
+        "/admin/products/{id}/inventory/adjust": {
             "post": {
-                "description": "Create a new user account with email and password",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Add or remove stock for a product and record the reason in the inventory ledger (requires inventory:write)",
                 "consumes": [
                     "application/json"
                 ],
@@ -178,23 +282,30 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Authentication"
+                    "Admin"
                 ],
-                "summary": "Register a new user",
+                "summary": "Adjust product inventory",
                 "parameters": [
                     {
-                        "description": "User registration data",
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Stock adjustment",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_dto.RegisterRequest"
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_dto.AdjustInventoryRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "User registered successfully",
+                    "200": {
+                        "description": "Inventory adjusted successfully",
                         "schema": {
                             "allOf": [
                                 {
@@ -204,7 +315,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_dto.AuthResponse"
+                                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_dto.InventoryMovementResponse"
                                         }
                                     }
                                 }
@@ -212,7 +323,25 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "Invalid request data or user already exists",
+                        "description": "Invalid request data or insufficient stock",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission inventory:write required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Product not found",
                         "schema": {
                             "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
                         }
@@ -220,24 +349,24 @@ const docTemplate = `{
                 }
             }
         },
-        "/cart": {
+        "/admin/roles": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieve current user's shopping cart with all items",
+                "description": "List every staff role with the permissions it grants (requires roles:manage)",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Cart"
+                    "Admin"
                 ],
-                "summary": "Get user's cart",
+                "summary": "Get roles",
                 "responses": {
                     "200": {
-                        "description": "Cart retrieved successfully",
+                        "description": "Roles retrieved successfully",
                         "schema": {
                             "allOf": [
                                 {
@@ -247,7 +376,10 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_dto.CartResponse"
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_dto.RoleResponse"
+                                            }
                                         }
                                     }
                                 }
@@ -260,8 +392,14 @@ const docTemplate = `{
                             "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
                         }
                     },
-                    "404": {
-                        "description": "Cart not found",
+                    "403": {
+                        "description": "Permission roles:manage required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
                         "schema": {
                             "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
                         }
@@ -269,38 +407,33 @@ const docTemplate = `{
                 }
             }
         },
-        "/cart/items": {
-            "post": {
+        "/admin/users/{id}/roles": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Add a product to the user's shopping cart",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "List the roles assigned to a user (requires roles:manage)",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Cart"
+                    "Admin"
                 ],
-                "summary": "Add item to cart",
+                "summary": "Get user roles",
                 "parameters": [
                     {
-                        "description": "Item to add to cart",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_dto.AddToCartRequest"
-                        }
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Item added to cart successfully",
+                        "description": "User roles retrieved successfully",
                         "schema": {
                             "allOf": [
                                 {
@@ -310,7 +443,10 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_dto.CartResponse"
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_dto.RoleResponse"
+                                            }
                                         }
                                     }
                                 }
@@ -318,7 +454,7 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "Invalid request data or insufficient stock",
+                        "description": "Invalid user ID",
                         "schema": {
                             "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
                         }
@@ -328,18 +464,28 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
                         }
-                    }
-                }
-            }
-        },
-        "/cart/items/{id}": {
-            "put": {
+                    },
+                    "403": {
+                        "description": "Permission roles:manage required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Update the quantity of an item in the user's cart",
+                "description": "Grant a staff role to a user. Returns the roles the user now holds (requires roles:manage)",
                 "consumes": [
                     "application/json"
                 ],
@@ -347,30 +493,30 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Cart"
+                    "Admin"
                 ],
-                "summary": "Update cart item quantity",
+                "summary": "Assign role",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Cart Item ID",
+                        "description": "User ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "New quantity",
+                        "description": "Role to assign",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_dto.UpdateCartItemRequest"
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_dto.AssignRoleRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Cart item updated successfully",
+                        "description": "Role assigned successfully",
                         "schema": {
                             "allOf": [
                                 {
@@ -380,7 +526,10 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_dto.CartResponse"
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_dto.RoleResponse"
+                                            }
                                         }
                                     }
                                 }
@@ -388,7 +537,7 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "Invalid request data or insufficient stock",
+                        "description": "Invalid request data or changing your own roles",
                         "schema": {
                             "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
                         }
@@ -398,38 +547,62 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
                         }
+                    },
+                    "403": {
+                        "description": "Permission roles:manage required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "User or role not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
                     }
                 }
-            },
+            }
+        },
+        "/admin/users/{id}/roles/{role}": {
             "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Remove an item from the user's shopping cart",
+                "description": "Take a staff role away from a user (requires roles:manage)",
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "Cart"
+                    "Admin"
                 ],
-                "summary": "Remove item from cart",
+                "summary": "Revoke role",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Cart Item ID",
+                        "description": "User ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Role name",
+                        "name": "role",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Item removed from cart successfully",
+                        "description": "Role revoked successfully",
                         "schema": {
                             "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
                         }
                     },
                     "400": {
-                        "description": "Invalid cart item ID",
+                        "description": "Invalid user ID or changing your own roles",
                         "schema": {
                             "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
                         }
@@ -439,57 +612,30 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
                         }
-                    }
-                }
-            }
-        },
-        "/categories": {
-            "get": {
-                "description": "Retrieve all active categories",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Categories"
-                ],
-                "summary": "Get all categories",
-                "responses": {
-                    "200": {
-                        "description": "Categories retrieved successfully",
+                    },
+                    "403": {
+                        "description": "Permission roles:manage required",
                         "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "type": "array",
-                                            "items": {
-                                                "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_dto.CategoryResponse"
-                                            }
-                                        }
-                                    }
-                                }
-                            ]
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
                         }
                     },
-                    "500": {
-                        "description": "Internal server error",
+                    "404": {
+                        "description": "User or role not found, or role not assigned",
                         "schema": {
                             "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
                         }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/admin/users/{id}/status": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Create a new product category (Admin only)",
+                "description": "Activate or deactivate a user account. Deactivation signs the user out of every session immediately (requires users:update)",
                 "consumes": [
                     "application/json"
                 ],
@@ -497,23 +643,30 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Categories"
+                    "Admin"
                 ],
-                "summary": "Create a new category",
+                "summary": "Update user status",
                 "parameters": [
                     {
-                        "description": "Category data",
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New account status",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_dto.CreateCategoryRequest"
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_dto.UpdateUserStatusRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Category created successfully",
+                    "200": {
+                        "description": "User status updated successfully",
                         "schema": {
                             "allOf": [
                                 {
@@ -523,7 +676,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_dto.CategoryResponse"
+                                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_dto.UserResponse"
                                         }
                                     }
                                 }
@@ -531,7 +684,7 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "Invalid request data",
+                        "description": "Invalid request data or deactivating your own account",
                         "schema": {
                             "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
                         }
@@ -543,7 +696,13 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "Admin access required",
+                        "description": "Permission users:update required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
                         "schema": {
                             "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
                         }
@@ -551,45 +710,33 @@ const docTemplate = `{
                 }
             }
         },
-        "/categories/{id}": {
-            "put": {
+        "/admin/users/{id}/unlock": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Update an existing category (Admin only)",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Lift a temporary login lockout and reset the failed login counter of a user (requires users:unlock)",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Categories"
+                    "Admin"
                 ],
-                "summary": "Update a category",
+                "summary": "Unlock user",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Category ID",
+                        "description": "User ID",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Category update data",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_dto.UpdateCategoryRequest"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Category updated successfully",
+                        "description": "User unlocked successfully",
                         "schema": {
                             "allOf": [
                                 {
@@ -599,7 +746,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_dto.CategoryResponse"
+                                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_dto.UserResponse"
                                         }
                                     }
                                 }
@@ -607,7 +754,7 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "Invalid request data",
+                        "description": "Invalid user ID",
                         "schema": {
                             "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
                         }
@@ -619,54 +766,13 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "Admin access required",
-                        "schema": {
-                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
-                        }
-                    }
-                }
-            },
-            "delete": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Delete a category (Admin only)",
-                "tags": [
-                    "Categories"
-                ],
-                "summary": "Delete a category",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Category ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "Category deleted successfully",
-                        "schema": {
-                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
-                        }
-                    },
-                    "400": {
-                        "description": "Invalid category ID",
-                        "schema": {
-                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
-                        }
-                    },
-                    "401": {
-                        "description": "Unauthorized",
+                        "description": "Permission users:unlock required",
                         "schema": {
                             "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
                         }
                     },
-                    "403": {
-                        "description": "Admin access required",
+                    "404": {
+                        "description": "User not found",
                         "schema": {
                             "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
                         }
@@ -674,21 +780,21 @@ const docTemplate = `{
                 }
             }
         },
-        "/orders": {
+        "/admin/webhooks": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieve paginated list of user's orders",
+                "description": "Retrieve a paginated list of webhook subscriptions (requires webhooks:manage)",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Orders"
+                    "Admin"
                 ],
-                "summary": "Get user's orders",
+                "summary": "Get webhook subscriptions",
                 "parameters": [
                     {
                         "type": "integer",
@@ -707,7 +813,7 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Orders retrieved successfully",
+                        "description": "Webhook subscriptions retrieved successfully",
                         "schema": {
                             "allOf": [
                                 {
@@ -719,7 +825,7 @@ const docTemplate = `{
                                         "data": {
                                             "type": "array",
                                             "items": {
-                                                "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_dto.OrderResponse"
+                                                "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_dto.WebhookSubscriptionResponse"
                                             }
                                         }
                                     }
@@ -733,6 +839,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
                         }
                     },
+                    "403": {
+                        "description": "Permission webhooks:manage required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -747,17 +859,31 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Create an order from the current user's cart",
+                "description": "Register a merchant endpoint to receive signed domain events. Event types can be ORDER_CREATED, ORDER_STATUS_CHANGED, PRODUCT_PRICE_CHANGED, PRODUCT_LOW_STOCK, PRODUCT_OUT_OF_STOCK or \"*\" for all of them. The signing secret is only returned in this response (requires webhooks:manage)",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Orders"
+                    "Admin"
+                ],
+                "summary": "Create webhook subscription",
+                "parameters": [
+                    {
+                        "description": "Webhook subscription data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_dto.CreateWebhookSubscriptionRequest"
+                        }
+                    }
                 ],
-                "summary": "Create an order",
                 "responses": {
                     "201": {
-                        "description": "Order created successfully",
+                        "description": "Webhook subscription created successfully",
                         "schema": {
                             "allOf": [
                                 {
@@ -767,7 +893,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_dto.OrderResponse"
+                                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_dto.WebhookSubscriptionResponse"
                                         }
                                     }
                                 }
@@ -775,7 +901,7 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "Cart is empty or insufficient stock",
+                        "description": "Invalid request data or unknown event type",
                         "schema": {
                             "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
                         }
@@ -785,29 +911,35 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
                         }
+                    },
+                    "403": {
+                        "description": "Permission webhooks:manage required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
                     }
                 }
             }
         },
-        "/orders/{id}": {
+        "/admin/webhooks/{id}": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieve detailed information about a specific order",
+                "description": "Retrieve a webhook subscription by ID (requires webhooks:manage)",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Orders"
+                    "Admin"
                 ],
-                "summary": "Get order by ID",
+                "summary": "Get webhook subscription",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Order ID",
+                        "description": "Webhook subscription ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -815,7 +947,7 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Order retrieved successfully",
+                        "description": "Webhook subscription retrieved successfully",
                         "schema": {
                             "allOf": [
                                 {
@@ -825,7 +957,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_dto.OrderResponse"
+                                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_dto.WebhookSubscriptionResponse"
                                         }
                                     }
                                 }
@@ -833,7 +965,7 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "Invalid order ID",
+                        "description": "Invalid webhook subscription ID",
                         "schema": {
                             "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
                         }
@@ -844,102 +976,58 @@ const docTemplate = `{
                             "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
                         }
                     },
+                    "403": {
+                        "description": "Permission webhooks:manage required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    },
                     "404": {
-                        "description": "Order not found",
+                        "description": "Webhook subscription not found",
                         "schema": {
                             "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
                         }
                     }
                 }
-            }
-        },
-        "/products": {
-            "get": {
-                "description": "Retrieve paginated list of active products",
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Change the URL, event types or active state of a webhook subscription (requires webhooks:manage)",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Products"
+                    "Admin"
                 ],
-                "summary": "Get all products",
+                "summary": "Update webhook subscription",
                 "parameters": [
                     {
                         "type": "integer",
-                        "default": 1,
-                        "description": "Page number",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "default": 10,
-                        "description": "Items per page",
-                        "name": "limit",
-                        "in": "query"
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "Products retrieved successfully",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.PaginatedResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "type": "array",
-                                            "items": {
-                                                "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_dto.ProductResponse"
-                                            }
-                                        }
-                                    }
-                                }
-                            ]
-                        }
+                        "description": "Webhook subscription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     },
-                    "500": {
-                        "description": "Internal server error",
-                        "schema": {
-                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
-                        }
-                    }
-                }
-            },
-            "post": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Create a new product (Admin only)",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Products"
-                ],
-                "summary": "Create a new product",
-                "parameters": [
                     {
-                        "description": "Product data",
+                        "description": "Webhook subscription data",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_dto.CreateProductRequest"
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_dto.UpdateWebhookSubscriptionRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Product created successfully",
+                    "200": {
+                        "description": "Webhook subscription updated successfully",
                         "schema": {
                             "allOf": [
                                 {
@@ -949,7 +1037,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_dto.ProductResponse"
+                                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_dto.WebhookSubscriptionResponse"
                                         }
                                     }
                                 }
@@ -957,7 +1045,7 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "Invalid request data",
+                        "description": "Invalid request data or unknown event type",
                         "schema": {
                             "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
                         }
@@ -969,28 +1057,37 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "Admin access required",
+                        "description": "Permission webhooks:manage required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Webhook subscription not found",
                         "schema": {
                             "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
                         }
                     }
                 }
-            }
-        },
-        "/products/{id}": {
-            "get": {
-                "description": "Retrieve detailed information about a specific product",
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete a webhook subscription; pending deliveries to it are abandoned (requires webhooks:manage)",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Products"
+                    "Admin"
                 ],
-                "summary": "Get a product by ID",
+                "summary": "Delete webhook subscription",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Product ID",
+                        "description": "Webhook subscription ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -998,85 +1095,92 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Product retrieved successfully",
+                        "description": "Webhook subscription deleted successfully",
                         "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_dto.ProductResponse"
-                                        }
-                                    }
-                                }
-                            ]
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
                         }
                     },
                     "400": {
-                        "description": "Invalid product ID",
+                        "description": "Invalid webhook subscription ID",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission webhooks:manage required",
                         "schema": {
                             "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
                         }
                     },
                     "404": {
-                        "description": "Product not found",
+                        "description": "Webhook subscription not found",
                         "schema": {
                             "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
                         }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/admin/webhooks/{id}/deliveries": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Update an existing product (Admin only)",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Retrieve the paginated delivery log of a webhook subscription, newest first (requires webhooks:manage)",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Products"
+                    "Admin"
                 ],
-                "summary": "Update a product",
+                "summary": "Get webhook deliveries",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Product ID",
+                        "description": "Webhook subscription ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Product update data",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_dto.UpdateProductRequest"
-                        }
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Items per page",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Product updated successfully",
+                        "description": "Webhook deliveries retrieved successfully",
                         "schema": {
                             "allOf": [
                                 {
-                                    "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                                    "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.PaginatedResponse"
                                 },
                                 {
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_dto.ProductResponse"
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_dto.WebhookDeliveryResponse"
+                                            }
                                         }
                                     }
                                 }
@@ -1084,7 +1188,7 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "Invalid request data",
+                        "description": "Invalid webhook subscription ID",
                         "schema": {
                             "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
                         }
@@ -1096,42 +1200,72 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "Admin access required",
+                        "description": "Permission webhooks:manage required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Webhook subscription not found",
                         "schema": {
                             "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/admin/webhooks/{id}/deliveries/{deliveryId}": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Delete a product (Admin only)",
+                "description": "Retrieve a webhook delivery with every attempt made for it (requires webhooks:manage)",
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "Products"
+                    "Admin"
                 ],
-                "summary": "Delete a product",
+                "summary": "Get webhook delivery",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Product ID",
+                        "description": "Webhook subscription ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Webhook delivery ID",
+                        "name": "deliveryId",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Product deleted successfully",
+                        "description": "Webhook delivery retrieved successfully",
                         "schema": {
-                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_dto.WebhookDeliveryResponse"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "400": {
-                        "description": "Invalid product ID",
+                        "description": "Invalid webhook subscription or delivery ID",
                         "schema": {
                             "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
                         }
@@ -1143,7 +1277,13 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "Admin access required",
+                        "description": "Permission webhooks:manage required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Webhook delivery not found",
                         "schema": {
                             "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
                         }
@@ -1151,43 +1291,40 @@ const docTemplate = `{
                 }
             }
         },
-        "/products/{id}/images": {
+        "/admin/webhooks/{id}/deliveries/{deliveryId}/redeliver": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Upload an image for a product (Admin only)",
-                "consumes": [
-                    "multipart/form-data"
-                ],
+                "description": "Queue a webhook delivery to be sent again immediately with a fresh retry budget (requires webhooks:manage)",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Products"
+                    "Admin"
                 ],
-                "summary": "Upload product image",
+                "summary": "Redeliver webhook",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Product ID",
+                        "description": "Webhook subscription ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "type": "file",
-                        "description": "Image file",
-                        "name": "image",
-                        "in": "formData",
+                        "type": "integer",
+                        "description": "Webhook delivery ID",
+                        "name": "deliveryId",
+                        "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Image uploaded successfully",
+                        "description": "Webhook delivery queued for redelivery",
                         "schema": {
                             "allOf": [
                                 {
@@ -1197,10 +1334,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "type": "object",
-                                            "additionalProperties": {
-                                                "type": "string"
-                                            }
+                                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_dto.WebhookDeliveryResponse"
                                         }
                                     }
                                 }
@@ -1208,7 +1342,7 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "Invalid request or file",
+                        "description": "Invalid webhook subscription or delivery ID",
                         "schema": {
                             "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
                         }
@@ -1220,7 +1354,13 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "Admin access required",
+                        "description": "Permission webhooks:manage required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Webhook delivery not found",
                         "schema": {
                             "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
                         }
@@ -1228,46 +1368,2017 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/profile": {
-            "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
+        "/auth/forgot-password": {
+            "post": {
+                "description": "Email a single-use password reset link. The response is the same whether or not the account exists",
+                "consumes": [
+                    "application/json"
                 ],
-                "description": "Get current authenticated user's profile information",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "Authentication"
+                ],
+                "summary": "Request a password reset",
+                "parameters": [
+                    {
+                        "description": "Account email",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_dto.ForgotPasswordRequest"
+                        }
+                    }
                 ],
-                "summary": "Get user profile",
                 "responses": {
                     "200": {
-                        "description": "Profile retrieved successfully",
+                        "description": "Password reset email sent if the account exists",
                         "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_dto.UserResponse"
-                                        }
-                                    }
-                                }
-                            ]
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "400": {
+                        "description": "Invalid request data",
                         "schema": {
                             "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
                         }
-                    },
+                    }
+                }
+            }
+        },
+        "/auth/login": {
+            "post": {
+                "description": "Authenticate user with email and password. Accounts with two-factor authentication get an mfa_token to complete the login at /auth/mfa/verify",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Authentication"
+                ],
+                "summary": "User login",
+                "parameters": [
+                    {
+                        "description": "User login credentials",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_dto.LoginRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Login successful, or an MFA challenge when two-factor authentication is enabled",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_dto.LoginResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid credentials",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many failed login attempts, see the Retry-After header",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/logout": {
+            "post": {
+                "description": "Invalidate refresh token and logout user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Authentication"
+                ],
+                "summary": "User logout",
+                "parameters": [
+                    {
+                        "description": "Refresh token to invalidate",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_dto.RefreshTokenRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Logout successful",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request data",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/mfa/verify": {
+            "post": {
+                "description": "Complete a login with the mfa_token from /auth/login and a TOTP code or a recovery code",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Authentication"
+                ],
+                "summary": "Verify two-factor authentication",
+                "parameters": [
+                    {
+                        "description": "MFA challenge token and code",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_dto.VerifyMFARequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Login successful",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_dto.AuthResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request data",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid or expired challenge, or invalid code",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many failed login attempts, see the Retry-After header",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/refresh": {
+            "post": {
+                "description": "Get a new access token using refresh token",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Authentication"
+                ],
+                "summary": "Refresh access token",
+                "parameters": [
+                    {
+                        "description": "Refresh token",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_dto.RefreshTokenRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Token refreshed successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_dto.AuthResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid refresh token",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/register": {
+            "post": {
+                "description": "Create a new user account with email and password",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Authentication"
+                ],
+                "summary": "Register a new user",
+                "parameters": [
+                    {
+                        "description": "User registration data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_dto.RegisterRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "User registered successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_dto.AuthResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request data or user already exists",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/resend-verification": {
+            "post": {
+                "description": "Send a new verification email to an unverified account. The response is the same whether or not the account exists",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Authentication"
+                ],
+                "summary": "Resend verification email",
+                "parameters": [
+                    {
+                        "description": "Account email",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_dto.ResendVerificationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Verification email sent if the account needs one",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request data",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/reset-password": {
+            "post": {
+                "description": "Set a new password with the token from the password reset email. All sessions of the account are signed out",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Authentication"
+                ],
+                "summary": "Reset password",
+                "parameters": [
+                    {
+                        "description": "Reset token and new password",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_dto.ResetPasswordRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Password reset successfully",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request data or invalid or expired reset token",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/verify-email": {
+            "post": {
+                "description": "Confirm the account's email address with the token from the verification email. Each token can be used once",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Authentication"
+                ],
+                "summary": "Verify email address",
+                "parameters": [
+                    {
+                        "description": "Verification token",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_dto.VerifyEmailRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Email verified successfully",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid or expired verification token",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/cart": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve current user's shopping cart with all items",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Cart"
+                ],
+                "summary": "Get user's cart",
+                "responses": {
+                    "200": {
+                        "description": "Cart retrieved successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_dto.CartResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Cart not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/cart/checkout": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Reserve stock for every item in the user's cart while they complete payment. Reservations expire after the configured TTL; calling this again restarts the timer.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Cart"
+                ],
+                "summary": "Begin checkout",
+                "responses": {
+                    "200": {
+                        "description": "Checkout started successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_dto.CheckoutResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Cart is empty or insufficient stock",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/cart/items": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Add a product to the user's shopping cart",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Cart"
+                ],
+                "summary": "Add item to cart",
+                "parameters": [
+                    {
+                        "description": "Item to add to cart",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_dto.AddToCartRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Unique key to safely retry this request",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Item added to cart successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_dto.CartResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request data or insufficient stock",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Idempotency-Key reused with a different request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/cart/items/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update the quantity of an item in the user's cart",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Cart"
+                ],
+                "summary": "Update cart item quantity",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Cart Item ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New quantity",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_dto.UpdateCartItemRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Cart item updated successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_dto.CartResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request data or insufficient stock",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Remove an item from the user's shopping cart",
+                "tags": [
+                    "Cart"
+                ],
+                "summary": "Remove item from cart",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Cart Item ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Item removed from cart successfully",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid cart item ID",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/categories": {
+            "get": {
+                "description": "Retrieve all active categories",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Categories"
+                ],
+                "summary": "Get all categories",
+                "responses": {
+                    "200": {
+                        "description": "Categories retrieved successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_dto.CategoryResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a new product category (requires categories:write)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Categories"
+                ],
+                "summary": "Create a new category",
+                "parameters": [
+                    {
+                        "description": "Category data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_dto.CreateCategoryRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Category created successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_dto.CategoryResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request data",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission categories:write required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/categories/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update an existing category (requires categories:write)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Categories"
+                ],
+                "summary": "Update a category",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Category ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Category update data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_dto.UpdateCategoryRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Category updated successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_dto.CategoryResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request data",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission categories:write required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete a category (requires categories:write)",
+                "tags": [
+                    "Categories"
+                ],
+                "summary": "Delete a category",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Category ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Category deleted successfully",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid category ID",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission categories:write required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve paginated list of user's orders",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Orders"
+                ],
+                "summary": "Get user's orders",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Items per page",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Orders retrieved successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.PaginatedResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_dto.OrderResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create an order from the current user's cart and authorize its payment",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Orders"
+                ],
+                "summary": "Create an order",
+                "parameters": [
+                    {
+                        "description": "Payment details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_dto.CreateOrderRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Unique key to safely retry this request",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Order created successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_dto.OrderResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request data or cart is empty",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    },
+                    "402": {
+                        "description": "Payment declined",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Email address is not verified",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Insufficient stock, another checkout is in progress or Idempotency-Key reused with a different request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Order could not be created",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve detailed information about a specific order",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Orders"
+                ],
+                "summary": "Get order by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Order retrieved successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_dto.OrderResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid order ID",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Order not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/{id}/cancel": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Cancel one of the current user's orders while it is pending or confirmed. Ordered items are returned to stock.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Orders"
+                ],
+                "summary": "Cancel an order",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Unique key to safely retry this request",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Order cancelled successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_dto.OrderResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid order ID or order can no longer be cancelled",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Order not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Idempotency-Key reused with a different request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/{id}/history": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve the status timeline of an order. Customers can view their own orders; staff with orders:read can view any order.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Orders"
+                ],
+                "summary": "Get order status history",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Order history retrieved successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_dto.OrderStatusHistoryResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid order ID",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Order not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/products": {
+            "get": {
+                "description": "Retrieve paginated list of active products",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Products"
+                ],
+                "summary": "Get all products",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Items per page",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Products retrieved successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.PaginatedResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_dto.ProductResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a new product (requires products:write)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Products"
+                ],
+                "summary": "Create a new product",
+                "parameters": [
+                    {
+                        "description": "Product data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_dto.CreateProductRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Product created successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_dto.ProductResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request data",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission products:write required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/{id}": {
+            "get": {
+                "description": "Retrieve detailed information about a specific product",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Products"
+                ],
+                "summary": "Get a product by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Product retrieved successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_dto.ProductResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid product ID",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Product not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update an existing product (requires products:write)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Products"
+                ],
+                "summary": "Update a product",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Product update data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_dto.UpdateProductRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Product updated successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_dto.ProductResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request data",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission products:write required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete a product (requires products:write)",
+                "tags": [
+                    "Products"
+                ],
+                "summary": "Delete a product",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Product deleted successfully",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid product ID",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission products:write required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/{id}/images": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Upload an image for a product (requires products:write)",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Products"
+                ],
+                "summary": "Upload product image",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Image file",
+                        "name": "image",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Image uploaded successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "object",
+                                            "additionalProperties": {
+                                                "type": "string"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request or file",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission products:write required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/mfa/recovery-codes": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Replace all recovery codes with new ones. Requires a TOTP code; the new codes are shown only once",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Regenerate recovery codes",
+                "parameters": [
+                    {
+                        "description": "TOTP code",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_dto.RegenerateRecoveryCodesRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Recovery codes regenerated successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_dto.RecoveryCodesResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request data, invalid code or not enabled",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/mfa/totp": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Generate a new TOTP secret and its otpauth:// provisioning URI to show as a QR code. Two-factor authentication is only turned on after confirming a code at /users/mfa/totp/enable",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Set up TOTP",
+                "responses": {
+                    "200": {
+                        "description": "TOTP secret generated successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_dto.TOTPSetupResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Two-factor authentication is already enabled",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/mfa/totp/disable": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Turn off two-factor authentication. Requires the password and a TOTP or recovery code",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Disable TOTP",
+                "parameters": [
+                    {
+                        "description": "Password and code",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_dto.DisableTOTPRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Two-factor authentication disabled successfully",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request data, incorrect password, invalid code or not enabled",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Two-factor authentication is required for this account",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/mfa/totp/enable": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Turn on two-factor authentication with a code from the authenticator app. Returns recovery codes, which are shown only once",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Enable TOTP",
+                "parameters": [
+                    {
+                        "description": "TOTP code",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_dto.EnableTOTPRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Two-factor authentication enabled successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_dto.RecoveryCodesResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request data, invalid code or TOTP not set up",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Two-factor authentication is already enabled",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/password": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Change the current user's password. Requires the current password and signs out every session",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Change password",
+                "parameters": [
+                    {
+                        "description": "Current and new password",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_dto.ChangePasswordRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Password changed successfully",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request data or incorrect current password",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/profile": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get current authenticated user's profile information",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Get user profile",
+                "responses": {
+                    "200": {
+                        "description": "Profile retrieved successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_dto.UserResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    },
                     "404": {
                         "description": "User not found",
                         "schema": {
@@ -1290,47 +3401,214 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "User"
+                ],
+                "summary": "Update user profile",
+                "parameters": [
+                    {
+                        "description": "Profile update data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_dto.UpdateProfileRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Profile updated successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_dto.UserResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request data",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/sessions": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the current user's signed-in sessions with the device and IP address they were issued to",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Get sessions",
+                "responses": {
+                    "200": {
+                        "description": "Sessions retrieved successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_dto.SessionResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/sessions/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Sign out one session of the current user by revoking its refresh token",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Revoke session",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Session ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Session revoked successfully",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid session ID",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Session not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/payments/{provider}": {
+            "post": {
+                "description": "Receive an asynchronous payment notification from a payment provider. The raw body must be signed with HMAC-SHA256 using the configured webhook secret and the hex digest sent in the X-Payment-Signature header. Replayed events are acknowledged without being processed again.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
                 ],
-                "summary": "Update user profile",
+                "summary": "Receive a payment webhook",
                 "parameters": [
                     {
-                        "description": "Profile update data",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_dto.UpdateProfileRequest"
-                        }
+                        "type": "string",
+                        "description": "Payment provider name",
+                        "name": "provider",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Hex encoded HMAC-SHA256 signature of the request body",
+                        "name": "X-Payment-Signature",
+                        "in": "header",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Profile updated successfully",
+                        "description": "Webhook processed successfully",
                         "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_dto.UserResponse"
-                                        }
-                                    }
-                                }
-                            ]
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
                         }
                     },
                     "400": {
-                        "description": "Invalid request data",
+                        "description": "Invalid webhook payload",
                         "schema": {
                             "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
                         }
                     },
                     "401": {
-                        "description": "Unauthorized",
+                        "description": "Invalid webhook signature",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Unknown payment provider",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to process webhook",
                         "schema": {
                             "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_utils.Response"
                         }
@@ -1356,6 +3634,44 @@ const docTemplate = `{
                 }
             }
         },
+        "github_com_tomimandalaputra_e-commerce-go_internal_dto.AdjustInventoryRequest": {
+            "type": "object",
+            "required": [
+                "delta",
+                "reason"
+            ],
+            "properties": {
+                "delta": {
+                    "type": "integer"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string",
+                    "enum": [
+                        "manual_adjustment",
+                        "return",
+                        "import"
+                    ]
+                },
+                "reference_id": {
+                    "type": "string",
+                    "maxLength": 100
+                }
+            }
+        },
+        "github_com_tomimandalaputra_e-commerce-go_internal_dto.AssignRoleRequest": {
+            "type": "object",
+            "required": [
+                "role"
+            ],
+            "properties": {
+                "role": {
+                    "type": "string"
+                }
+            }
+        },
         "github_com_tomimandalaputra_e-commerce-go_internal_dto.AuthResponse": {
             "type": "object",
             "properties": {
@@ -1442,6 +3758,36 @@ const docTemplate = `{
                 }
             }
         },
+        "github_com_tomimandalaputra_e-commerce-go_internal_dto.ChangePasswordRequest": {
+            "type": "object",
+            "required": [
+                "current_password",
+                "new_password"
+            ],
+            "properties": {
+                "current_password": {
+                    "type": "string"
+                },
+                "new_password": {
+                    "type": "string",
+                    "minLength": 8
+                }
+            }
+        },
+        "github_com_tomimandalaputra_e-commerce-go_internal_dto.CheckoutResponse": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "type": "string"
+                },
+                "reservations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_dto.StockReservationResponse"
+                    }
+                }
+            }
+        },
         "github_com_tomimandalaputra_e-commerce-go_internal_dto.CreateCategoryRequest": {
             "type": "object",
             "required": [
@@ -1456,6 +3802,17 @@ const docTemplate = `{
                 }
             }
         },
+        "github_com_tomimandalaputra_e-commerce-go_internal_dto.CreateOrderRequest": {
+            "type": "object",
+            "required": [
+                "payment_token"
+            ],
+            "properties": {
+                "payment_token": {
+                    "type": "string"
+                }
+            }
+        },
         "github_com_tomimandalaputra_e-commerce-go_internal_dto.CreateProductRequest": {
             "type": "object",
             "required": [
@@ -1471,6 +3828,10 @@ const docTemplate = `{
                 "description": {
                     "type": "string"
                 },
+                "low_stock_threshold": {
+                    "type": "integer",
+                    "minimum": 0
+                },
                 "name": {
                     "type": "string"
                 },
@@ -1486,6 +3847,99 @@ const docTemplate = `{
                 }
             }
         },
+        "github_com_tomimandalaputra_e-commerce-go_internal_dto.CreateWebhookSubscriptionRequest": {
+            "type": "object",
+            "required": [
+                "event_types",
+                "url"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "event_types": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "url": {
+                    "type": "string",
+                    "maxLength": 2048
+                }
+            }
+        },
+        "github_com_tomimandalaputra_e-commerce-go_internal_dto.DisableTOTPRequest": {
+            "type": "object",
+            "required": [
+                "code",
+                "password"
+            ],
+            "properties": {
+                "code": {
+                    "description": "Code is a TOTP code or a recovery code",
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_tomimandalaputra_e-commerce-go_internal_dto.EnableTOTPRequest": {
+            "type": "object",
+            "required": [
+                "code"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_tomimandalaputra_e-commerce-go_internal_dto.ForgotPasswordRequest": {
+            "type": "object",
+            "required": [
+                "email"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_tomimandalaputra_e-commerce-go_internal_dto.InventoryMovementResponse": {
+            "type": "object",
+            "properties": {
+                "actor_id": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "delta": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "reference_id": {
+                    "type": "string"
+                },
+                "stock_after": {
+                    "type": "integer"
+                }
+            }
+        },
         "github_com_tomimandalaputra_e-commerce-go_internal_dto.LoginRequest": {
             "type": "object",
             "required": [
@@ -1501,6 +3955,55 @@ const docTemplate = `{
                 }
             }
         },
+        "github_com_tomimandalaputra_e-commerce-go_internal_dto.LoginResponse": {
+            "type": "object",
+            "properties": {
+                "access_token": {
+                    "type": "string"
+                },
+                "mfa_required": {
+                    "type": "boolean"
+                },
+                "mfa_token": {
+                    "type": "string"
+                },
+                "mfa_token_expires_at": {
+                    "type": "string"
+                },
+                "refresh_token": {
+                    "type": "string"
+                },
+                "user": {
+                    "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_dto.UserResponse"
+                }
+            }
+        },
+        "github_com_tomimandalaputra_e-commerce-go_internal_dto.LowStockProductResponse": {
+            "type": "object",
+            "properties": {
+                "available": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "low_stock_threshold": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "out_of_stock": {
+                    "type": "boolean"
+                },
+                "sku": {
+                    "type": "string"
+                },
+                "stock": {
+                    "type": "integer"
+                }
+            }
+        },
         "github_com_tomimandalaputra_e-commerce-go_internal_dto.OrderItemResponse": {
             "type": "object",
             "properties": {
@@ -1527,20 +4030,75 @@ const docTemplate = `{
                 "id": {
                     "type": "integer"
                 },
-                "order_items": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_dto.OrderItemResponse"
-                    }
+                "order_items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_dto.OrderItemResponse"
+                    }
+                },
+                "payment": {
+                    "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_dto.PaymentResponse"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "status_history": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_dto.OrderStatusHistoryResponse"
+                    }
+                },
+                "total_amount": {
+                    "type": "number"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_tomimandalaputra_e-commerce-go_internal_dto.OrderStatusHistoryResponse": {
+            "type": "object",
+            "properties": {
+                "actor_id": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "from_status": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "to_status": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_tomimandalaputra_e-commerce-go_internal_dto.PaymentResponse": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "failure_reason": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "provider": {
+                    "type": "string"
                 },
                 "status": {
                     "type": "string"
-                },
-                "total_amount": {
-                    "type": "number"
-                },
-                "user_id": {
-                    "type": "integer"
                 }
             }
         },
@@ -1567,6 +4125,9 @@ const docTemplate = `{
         "github_com_tomimandalaputra_e-commerce-go_internal_dto.ProductResponse": {
             "type": "object",
             "properties": {
+                "available": {
+                    "type": "integer"
+                },
                 "category": {
                     "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_dto.CategoryResponse"
                 },
@@ -1608,6 +4169,17 @@ const docTemplate = `{
                 }
             }
         },
+        "github_com_tomimandalaputra_e-commerce-go_internal_dto.RecoveryCodesResponse": {
+            "type": "object",
+            "properties": {
+                "recovery_codes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
         "github_com_tomimandalaputra_e-commerce-go_internal_dto.RefreshTokenRequest": {
             "type": "object",
             "required": [
@@ -1619,6 +4191,17 @@ const docTemplate = `{
                 }
             }
         },
+        "github_com_tomimandalaputra_e-commerce-go_internal_dto.RegenerateRecoveryCodesRequest": {
+            "type": "object",
+            "required": [
+                "code"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string"
+                }
+            }
+        },
         "github_com_tomimandalaputra_e-commerce-go_internal_dto.RegisterRequest": {
             "type": "object",
             "required": [
@@ -1646,6 +4229,105 @@ const docTemplate = `{
                 }
             }
         },
+        "github_com_tomimandalaputra_e-commerce-go_internal_dto.ResendVerificationRequest": {
+            "type": "object",
+            "required": [
+                "email"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_tomimandalaputra_e-commerce-go_internal_dto.ResetPasswordRequest": {
+            "type": "object",
+            "required": [
+                "password",
+                "token"
+            ],
+            "properties": {
+                "password": {
+                    "type": "string",
+                    "minLength": 8
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_tomimandalaputra_e-commerce-go_internal_dto.RoleResponse": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "permissions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "github_com_tomimandalaputra_e-commerce-go_internal_dto.SessionResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "ip_address": {
+                    "type": "string"
+                },
+                "user_agent": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_tomimandalaputra_e-commerce-go_internal_dto.StockReservationResponse": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "product_name": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_tomimandalaputra_e-commerce-go_internal_dto.TOTPSetupResponse": {
+            "type": "object",
+            "properties": {
+                "provisioning_uri": {
+                    "description": "ProvisioningURI is the otpauth:// URI to show as a QR code",
+                    "type": "string"
+                },
+                "secret": {
+                    "type": "string"
+                }
+            }
+        },
         "github_com_tomimandalaputra_e-commerce-go_internal_dto.UpdateCartItemRequest": {
             "type": "object",
             "required": [
@@ -1675,6 +4357,27 @@ const docTemplate = `{
                 }
             }
         },
+        "github_com_tomimandalaputra_e-commerce-go_internal_dto.UpdateOrderStatusRequest": {
+            "type": "object",
+            "required": [
+                "status"
+            ],
+            "properties": {
+                "reason": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "pending",
+                        "confirmed",
+                        "shipped",
+                        "delivered",
+                        "cancelled"
+                    ]
+                }
+            }
+        },
         "github_com_tomimandalaputra_e-commerce-go_internal_dto.UpdateProductRequest": {
             "type": "object",
             "required": [
@@ -1692,6 +4395,10 @@ const docTemplate = `{
                 "is_active": {
                     "type": "boolean"
                 },
+                "low_stock_threshold": {
+                    "type": "integer",
+                    "minimum": 0
+                },
                 "name": {
                     "type": "string"
                 },
@@ -1722,6 +4429,43 @@ const docTemplate = `{
                 }
             }
         },
+        "github_com_tomimandalaputra_e-commerce-go_internal_dto.UpdateUserStatusRequest": {
+            "type": "object",
+            "required": [
+                "is_active"
+            ],
+            "properties": {
+                "is_active": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "github_com_tomimandalaputra_e-commerce-go_internal_dto.UpdateWebhookSubscriptionRequest": {
+            "type": "object",
+            "required": [
+                "event_types",
+                "url"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "event_types": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "url": {
+                    "type": "string",
+                    "maxLength": 2048
+                }
+            }
+        },
         "github_com_tomimandalaputra_e-commerce-go_internal_dto.UserResponse": {
             "type": "object",
             "properties": {
@@ -1731,6 +4475,9 @@ const docTemplate = `{
                 "email": {
                     "type": "string"
                 },
+                "email_verified": {
+                    "type": "boolean"
+                },
                 "first_name": {
                     "type": "string"
                 },
@@ -1743,14 +4490,141 @@ const docTemplate = `{
                 "last_name": {
                     "type": "string"
                 },
+                "mfa_enabled": {
+                    "type": "boolean"
+                },
                 "phone": {
                     "type": "string"
                 },
-                "role": {
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_tomimandalaputra_e-commerce-go_internal_dto.VerifyEmailRequest": {
+            "type": "object",
+            "required": [
+                "token"
+            ],
+            "properties": {
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_tomimandalaputra_e-commerce-go_internal_dto.VerifyMFARequest": {
+            "type": "object",
+            "required": [
+                "code",
+                "mfa_token"
+            ],
+            "properties": {
+                "code": {
+                    "description": "Code is a TOTP code or a recovery code",
+                    "type": "string"
+                },
+                "mfa_token": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_tomimandalaputra_e-commerce-go_internal_dto.WebhookDeliveryAttemptResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "duration_ms": {
+                    "type": "integer"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "response_body": {
+                    "type": "string"
+                },
+                "status_code": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_tomimandalaputra_e-commerce-go_internal_dto.WebhookDeliveryResponse": {
+            "type": "object",
+            "properties": {
+                "attempt_count": {
+                    "type": "integer"
+                },
+                "attempts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_tomimandalaputra_e-commerce-go_internal_dto.WebhookDeliveryAttemptResponse"
+                    }
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "delivered_at": {
+                    "type": "string"
+                },
+                "event_id": {
+                    "type": "string"
+                },
+                "event_type": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "last_error": {
+                    "type": "string"
+                },
+                "last_status_code": {
+                    "type": "integer"
+                },
+                "next_attempt_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "subscription_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_tomimandalaputra_e-commerce-go_internal_dto.WebhookSubscriptionResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "event_types": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "secret": {
+                    "description": "Secret is only returned when the subscription is created",
                     "type": "string"
                 },
                 "updated_at": {
                     "type": "string"
+                },
+                "url": {
+                    "type": "string"
                 }
             }
         },
@@ -1,8 +1,11 @@
 package config
 
 import (
+	"errors"
+	"fmt"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
@@ -10,17 +13,31 @@ import (
 
 // Config holds the application configuration.
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
-	JWT      JWTConfig
-	AWS      AWSConfig
-	Upload   UploadConfig
+	Server    ServerConfig
+	Database  DatabaseConfig
+	JWT       JWTConfig
+	Auth      AuthConfig
+	AWS       AWSConfig
+	Upload    UploadConfig
+	Payment   PaymentConfig
+	Inventory InventoryConfig
+	Outbox    OutboxConfig
+	Worker    WorkerConfig
+	Events    EventsConfig
+	Webhook   WebhookConfig
+	Mail      MailConfig
 }
 
 // ServerConfig holds the server configuration.
 type ServerConfig struct {
-	Port    string
-	GinMode string
+	Port              string
+	GinMode           string
+	IdempotencyKeyTTL time.Duration
+	// Expired idempotency keys are purged every IdempotencyPurgeInterval
+	IdempotencyPurgeInterval time.Duration
+
+	// FrontendURL is where links in emails point to
+	FrontendURL string
 }
 
 // DatabaseConfig holds the database connection configuration.
@@ -35,20 +52,95 @@ type DatabaseConfig struct {
 
 // JWTConfig holds the JWT authentication configuration.
 type JWTConfig struct {
+	// Secret signs tokens with HS256 when no SigningKeyFile is set, and is then required
 	Secret              string
 	ExpiresIn           time.Duration
 	RefreshTokenExpires time.Duration
+
+	// Issuer and Audience fill the iss and aud claims of access tokens, and must match to accept them
+	Issuer   string
+	Audience string
+
+	// SigningKeyFile is a PEM encoded RSA or Ed25519 private key; tokens are then signed with RS256 or EdDSA
+	SigningKeyFile string
+	// VerificationKeyFiles are PEM encoded public keys still accepted while rotating keys
+	VerificationKeyFiles []string
+}
+
+// AuthConfig holds the account verification configuration.
+type AuthConfig struct {
+	// TokenEncryptionKey encrypts emailed one-time tokens until the worker has sent them
+	TokenEncryptionKey string
+	// TokenHashSecret keys the HMAC stored for emailed tokens, MFA challenges and recovery codes.
+	// Tokens and codes hashed under PreviousTokenHashSecret are still accepted after changing it
+	TokenHashSecret         string
+	PreviousTokenHashSecret string
+
+	EmailVerificationTTL time.Duration
+	// VerificationResendCooldown is the minimum time between two verification emails
+	VerificationResendCooldown time.Duration
+	// RequireVerifiedEmail blocks checkout for accounts that have not verified their email
+	RequireVerifiedEmail bool
+
+	PasswordResetTTL time.Duration
+	// PasswordResetRateLimit is how many reset emails an account can get per PasswordResetRateWindow
+	PasswordResetRateLimit  int
+	PasswordResetRateWindow time.Duration
+
+	// RevocationCacheSize and RevocationCacheTTL size the in-memory cache of revoked access tokens
+	RevocationCacheSize     int
+	RevocationCacheTTL      time.Duration
+	RevocationPurgeInterval time.Duration
+
+	// PermissionCacheSize and PermissionCacheTTL size the in-memory cache of user permissions
+	PermissionCacheSize int
+	PermissionCacheTTL  time.Duration
+
+	Login LoginPolicy
+	MFA   MFAConfig
+}
+
+// LoginPolicy holds the password login throttling and lockout policy.
+type LoginPolicy struct {
+	// MaxFailedAttempts consecutive wrong passwords lock the account for LockoutDuration
+	MaxFailedAttempts int
+	LockoutDuration   time.Duration
+	// IPMaxFailedAttempts failures from one IP address within IPWindow block it until they age out
+	IPMaxFailedAttempts int
+	IPWindow            time.Duration
+	// Each attempt holds off the next one for DelayBase, doubling with every consecutive failure up to MaxDelay
+	DelayBase time.Duration
+	MaxDelay  time.Duration
+	// Login attempts older than AttemptRetention are purged every AttemptPurgeInterval
+	AttemptRetention     time.Duration
+	AttemptPurgeInterval time.Duration
+}
+
+// MFAConfig holds the TOTP two-factor authentication configuration.
+type MFAConfig struct {
+	// Issuer is the account name shown in authenticator apps
+	Issuer string
+	// EncryptionKey encrypts TOTP secrets at rest
+	EncryptionKey string
+	// ChallengeTTL is how long the second login step may take; a challenge allows ChallengeMaxAttempts codes
+	ChallengeTTL         time.Duration
+	ChallengeMaxAttempts int
+	RecoveryCodeCount    int
+	// RequireForAdmins refuses staff endpoints to accounts without two-factor
+	// authentication, and stops accounts holding a role from turning it off
+	RequireForAdmins bool
 }
 
 // AWSConfig holds the configuration for AWS or S3-compatible services (MinIO, LocalStack).
 type AWSConfig struct {
-	Region          string
-	AccessKeyID     string
-	SecretAccessKey string
-	S3Bucket        string
-	S3Endpoint      string
-	SQSEndpoint     string
-	EventQueueName  string
+	Region              string
+	AccessKeyID         string
+	SecretAccessKey     string
+	S3Bucket            string
+	S3Endpoint          string
+	SQSEndpoint         string
+	EventQueueName      string
+	DeadLetterQueueName string
 }
 
 // UploadConfig holds the file upload configuration.
@@ -60,18 +152,164 @@ type UploadConfig struct {
 	UploadProvider string
 }
 
+// PaymentConfig holds the payment gateway configuration.
+type PaymentConfig struct {
+	// Provider selects the payment gateway, currently only fake
+	Provider      string
+	Currency      string
+	WebhookSecret string
+	// Checkouts still awaiting their payment after AuthorizationWindow are
+	// cancelled, and failed voids retried, every SweepInterval
+	AuthorizationWindow time.Duration
+	SweepInterval       time.Duration
+}
+
+// InventoryConfig holds the stock reservation configuration.
+type InventoryConfig struct {
+	ReservationTTL           time.Duration
+	ReservationSweepInterval time.Duration
+}
+
+// OutboxConfig holds the configuration of the relay that publishes outbox events.
+type OutboxConfig struct {
+	PollInterval   time.Duration
+	BatchSize      int
+	RetryBaseDelay time.Duration
+	RetryMaxDelay  time.Duration
+	// ClaimTimeout is how long a relay may take to publish a batch before another relay picks it up
+	ClaimTimeout time.Duration
+	// Events failing MaxAttempts times are marked dead and no longer retried
+	MaxAttempts int
+	// Published events older than Retention are purged every PurgeInterval
+	Retention     time.Duration
+	PurgeInterval time.Duration
+}
+
+// EventsConfig holds the event publishing configuration.
+type EventsConfig struct {
+	// Publisher can be sqs, or gochannel to pass events from the outbox relay
+	// to the handlers inside the worker process without a queue
+	Publisher string
+}
+
+// WebhookConfig holds the configuration of outgoing merchant webhook deliveries.
+type WebhookConfig struct {
+	DeliveryInterval time.Duration
+	BatchSize        int
+	Timeout          time.Duration
+	MaxAttempts      int
+	RetryBaseDelay   time.Duration
+	RetryMaxDelay    time.Duration
+}
+
+// MailConfig holds the configuration of customer email notifications.
+type MailConfig struct {
+	// Transport can be smtp or file
+	Transport    string
+	From         string
+	FilePath     string
+	SMTPHost     string
+	SMTPPort     string
+	SMTPUsername string
+	SMTPPassword string
+}
+
+// WorkerConfig holds the configuration of the event consumer.
+type WorkerConfig struct {
+	MaxRetries           int
+	RetryInitialInterval time.Duration
+	RetryMaxInterval     time.Duration
+}
+
 // Load reads configuration from environment variables and returns a Config.
 func Load() (*Config, error) {
 	_ = godotenv.Load()
 
+	tokenEncryptionKey, err := requireEnv("TOKEN_ENCRYPTION_KEY")
+	if err != nil {
+		return nil, err
+	}
+
+	mfaEncryptionKey, err := requireEnv("MFA_ENCRYPTION_KEY")
+	if err != nil {
+		return nil, err
+	}
+
+	tokenHashSecret, err := requireEnv("TOKEN_HASH_SECRET")
+	if err != nil {
+		return nil, err
+	}
+
+	// A known secret would let anyone sign payment webhooks that confirm or cancel orders
+	paymentWebhookSecret, err := requireEnv("PAYMENT_WEBHOOK_SECRET")
+	if err != nil {
+		return nil, err
+	}
+
+	// Without a signing key tokens are signed with HS256, so a guessable default secret would let anyone forge them
+	jwtSecret := getEnv("JWT_SECRET", "")
+	jwtSigningKeyFile := getEnv("JWT_SIGNING_KEY_FILE", "")
+	if jwtSecret == "" && jwtSigningKeyFile == "" {
+		return nil, errors.New("JWT_SECRET must be set when JWT_SIGNING_KEY_FILE is empty")
+	}
+
 	jwtExpiresIn, _ := time.ParseDuration(getEnv("JWT_EXPIRES_IN", "24h"))
 	refreshTokenExpires, _ := time.ParseDuration(getEnv("REFRESH_TOKEN_EXPIRES_IN", "72h"))
+	idempotencyKeyTTL, _ := time.ParseDuration(getEnv("IDEMPOTENCY_KEY_TTL", "24h"))
+	idempotencyPurgeInterval, _ := time.ParseDuration(getEnv("IDEMPOTENCY_PURGE_INTERVAL", "1h"))
+	paymentAuthorizationWindow, _ := time.ParseDuration(getEnv("PAYMENT_AUTHORIZATION_WINDOW", "30m"))
+	paymentSweepInterval, _ := time.ParseDuration(getEnv("PAYMENT_SWEEP_INTERVAL", "1m"))
+	reservationTTL, _ := time.ParseDuration(getEnv("RESERVATION_TTL", "15m"))
+	reservationSweepInterval, _ := time.ParseDuration(getEnv("RESERVATION_SWEEP_INTERVAL", "1m"))
+	outboxPollInterval, _ := time.ParseDuration(getEnv("OUTBOX_POLL_INTERVAL", "1s"))
+	outboxBatchSize, _ := strconv.Atoi(getEnv("OUTBOX_BATCH_SIZE", "100"))
+	outboxRetryBaseDelay, _ := time.ParseDuration(getEnv("OUTBOX_RETRY_BASE_DELAY", "1s"))
+	outboxRetryMaxDelay, _ := time.ParseDuration(getEnv("OUTBOX_RETRY_MAX_DELAY", "5m"))
+	outboxClaimTimeout, _ := time.ParseDuration(getEnv("OUTBOX_CLAIM_TIMEOUT", "1m"))
+	outboxMaxAttempts, _ := strconv.Atoi(getEnv("OUTBOX_MAX_ATTEMPTS", "20"))
+	outboxRetention, _ := time.ParseDuration(getEnv("OUTBOX_RETENTION", "168h"))
+	outboxPurgeInterval, _ := time.ParseDuration(getEnv("OUTBOX_PURGE_INTERVAL", "1h"))
+	workerMaxRetries, _ := strconv.Atoi(getEnv("WORKER_MAX_RETRIES", "3"))
+	workerRetryInitialInterval, _ := time.ParseDuration(getEnv("WORKER_RETRY_INITIAL_INTERVAL", "1s"))
+	workerRetryMaxInterval, _ := time.ParseDuration(getEnv("WORKER_RETRY_MAX_INTERVAL", "30s"))
+	webhookDeliveryInterval, _ := time.ParseDuration(getEnv("WEBHOOK_DELIVERY_INTERVAL", "5s"))
+	webhookBatchSize, _ := strconv.Atoi(getEnv("WEBHOOK_BATCH_SIZE", "50"))
+	webhookTimeout, _ := time.ParseDuration(getEnv("WEBHOOK_TIMEOUT", "10s"))
+	webhookMaxAttempts, _ := strconv.Atoi(getEnv("WEBHOOK_MAX_ATTEMPTS", "8"))
+	webhookRetryBaseDelay, _ := time.ParseDuration(getEnv("WEBHOOK_RETRY_BASE_DELAY", "30s"))
+	webhookRetryMaxDelay, _ := time.ParseDuration(getEnv("WEBHOOK_RETRY_MAX_DELAY", "6h"))
+	emailVerificationTTL, _ := time.ParseDuration(getEnv("EMAIL_VERIFICATION_TTL", "24h"))
+	verificationResendCooldown, _ := time.ParseDuration(getEnv("VERIFICATION_RESEND_COOLDOWN", "1m"))
+	requireVerifiedEmail, _ := strconv.ParseBool(getEnv("REQUIRE_VERIFIED_EMAIL", "false"))
+	passwordResetTTL, _ := time.ParseDuration(getEnv("PASSWORD_RESET_TTL", "1h"))
+	passwordResetRateLimit, _ := strconv.Atoi(getEnv("PASSWORD_RESET_RATE_LIMIT", "3"))
+	passwordResetRateWindow, _ := time.ParseDuration(getEnv("PASSWORD_RESET_RATE_WINDOW", "1h"))
+	revocationCacheSize, _ := strconv.Atoi(getEnv("TOKEN_REVOCATION_CACHE_SIZE", "10000"))
+	revocationCacheTTL, _ := time.ParseDuration(getEnv("TOKEN_REVOCATION_CACHE_TTL", "30s"))
+	revocationPurgeInterval, _ := time.ParseDuration(getEnv("TOKEN_REVOCATION_PURGE_INTERVAL", "1h"))
+	permissionCacheSize, _ := strconv.Atoi(getEnv("PERMISSION_CACHE_SIZE", "10000"))
+	permissionCacheTTL, _ := time.ParseDuration(getEnv("PERMISSION_CACHE_TTL", "30s"))
+	loginMaxFailedAttempts, _ := strconv.Atoi(getEnv("LOGIN_MAX_FAILED_ATTEMPTS", "5"))
+	loginLockoutDuration, _ := time.ParseDuration(getEnv("LOGIN_LOCKOUT_DURATION", "15m"))
+	loginIPMaxFailedAttempts, _ := strconv.Atoi(getEnv("LOGIN_IP_MAX_FAILED_ATTEMPTS", "20"))
+	loginIPWindow, _ := time.ParseDuration(getEnv("LOGIN_IP_WINDOW", "15m"))
+	loginDelayBase, _ := time.ParseDuration(getEnv("LOGIN_DELAY_BASE", "500ms"))
+	loginMaxDelay, _ := time.ParseDuration(getEnv("LOGIN_MAX_DELAY", "5s"))
+	loginAttemptRetention, _ := time.ParseDuration(getEnv("LOGIN_ATTEMPT_RETENTION", "168h"))
+	loginAttemptPurgeInterval, _ := time.ParseDuration(getEnv("LOGIN_ATTEMPT_PURGE_INTERVAL", "1h"))
+	mfaChallengeTTL, _ := time.ParseDuration(getEnv("MFA_CHALLENGE_TTL", "5m"))
+	mfaChallengeMaxAttempts, _ := strconv.Atoi(getEnv("MFA_CHALLENGE_MAX_ATTEMPTS", "5"))
+	mfaRecoveryCodeCount, _ := strconv.Atoi(getEnv("MFA_RECOVERY_CODE_COUNT", "10"))
+	mfaRequireForAdmins, _ := strconv.ParseBool(getEnv("MFA_REQUIRE_FOR_ADMINS", "false"))
 	maxUploadSize, _ := strconv.ParseInt(getEnv("MAX_UPLOAD_SIZE", "10485760"), 10, 64)
 
 	return &Config{
 		Server: ServerConfig{
-			Port:    getEnv("PORT", "8080"),
-			GinMode: getEnv("GIN_MODE", "debug"),
+			Port:                     getEnv("PORT", "8080"),
+			GinMode:                  getEnv("GIN_MODE", "debug"),
+			IdempotencyKeyTTL:        idempotencyKeyTTL,
+			IdempotencyPurgeInterval: idempotencyPurgeInterval,
+			FrontendURL:              getEnv("FRONTEND_URL", "http://localhost:3000"),
 		},
 		Database: DatabaseConfig{
 			Host:     getEnv("DB_HOST", "localhost"),
@@ -82,24 +320,109 @@ func Load() (*Config, error) {
 			SSLMode:  getEnv("DB_SSL_MODE", "disable"),
 		},
 		JWT: JWTConfig{
-			Secret:              getEnv("JWT_SECRET", "your-super-secret-jwt-key"),
-			ExpiresIn:           jwtExpiresIn,
-			RefreshTokenExpires: refreshTokenExpires,
+			Secret:               jwtSecret,
+			ExpiresIn:            jwtExpiresIn,
+			RefreshTokenExpires:  refreshTokenExpires,
+			Issuer:               getEnv("JWT_ISSUER", "e-commerce-api"),
+			Audience:             getEnv("JWT_AUDIENCE", "e-commerce"),
+			SigningKeyFile:       jwtSigningKeyFile,
+			VerificationKeyFiles: getEnvList("JWT_VERIFICATION_KEY_FILES"),
+		},
+		Auth: AuthConfig{
+			TokenEncryptionKey:         tokenEncryptionKey,
+			TokenHashSecret:            tokenHashSecret,
+			PreviousTokenHashSecret:    getEnv("TOKEN_HASH_PREVIOUS_SECRET", ""),
+			EmailVerificationTTL:       emailVerificationTTL,
+			VerificationResendCooldown: verificationResendCooldown,
+			RequireVerifiedEmail:       requireVerifiedEmail,
+			PasswordResetTTL:           passwordResetTTL,
+			PasswordResetRateLimit:     passwordResetRateLimit,
+			PasswordResetRateWindow:    passwordResetRateWindow,
+			RevocationCacheSize:        revocationCacheSize,
+			RevocationCacheTTL:         revocationCacheTTL,
+			RevocationPurgeInterval:    revocationPurgeInterval,
+			PermissionCacheSize:        permissionCacheSize,
+			PermissionCacheTTL:         permissionCacheTTL,
+			Login: LoginPolicy{
+				MaxFailedAttempts:    loginMaxFailedAttempts,
+				LockoutDuration:      loginLockoutDuration,
+				IPMaxFailedAttempts:  loginIPMaxFailedAttempts,
+				IPWindow:             loginIPWindow,
+				DelayBase:            loginDelayBase,
+				MaxDelay:             loginMaxDelay,
+				AttemptRetention:     loginAttemptRetention,
+				AttemptPurgeInterval: loginAttemptPurgeInterval,
+			},
+			MFA: MFAConfig{
+				Issuer:               getEnv("MFA_ISSUER", "E-Commerce Shop"),
+				EncryptionKey:        mfaEncryptionKey,
+				ChallengeTTL:         mfaChallengeTTL,
+				ChallengeMaxAttempts: mfaChallengeMaxAttempts,
+				RecoveryCodeCount:    mfaRecoveryCodeCount,
+				RequireForAdmins:     mfaRequireForAdmins,
+			},
 		},
 		AWS: AWSConfig{
-			Region:          getEnv("AWS_REGION", "us-east-1"),
-			AccessKeyID:     getEnv("AWS_ACCESS_KEY_ID", "test"),
-			SecretAccessKey: getEnv("AWS_SECRET_ACCESS_KEY", "testpassword"),
-			S3Bucket:        getEnv("AWS_S3_BUCKET", "ecommerce-uploads"),
-			S3Endpoint:      getEnv("AWS_S3_ENDPOINT", "http://localhost:4566"),
-			SQSEndpoint:     getEnv("AWS_SQS_ENDPOINT", "http://localhost:4576"),
-			EventQueueName:  getEnv("AWS_EVENT_QUEUE_NAME", "ecommerce-events"),
+			Region:              getEnv("AWS_REGION", "us-east-1"),
+			AccessKeyID:         getEnv("AWS_ACCESS_KEY_ID", "test"),
+			SecretAccessKey:     getEnv("AWS_SECRET_ACCESS_KEY", "testpassword"),
+			S3Bucket:            getEnv("AWS_S3_BUCKET", "ecommerce-uploads"),
+			S3Endpoint:          getEnv("AWS_S3_ENDPOINT", "http://localhost:4566"),
+			SQSEndpoint:         getEnv("AWS_SQS_ENDPOINT", "http://localhost:4576"),
+			EventQueueName:      getEnv("AWS_EVENT_QUEUE_NAME", "ecommerce-events"),
+			DeadLetterQueueName: getEnv("AWS_DEAD_LETTER_QUEUE_NAME", "ecommerce-events-dlq"),
 		},
 		Upload: UploadConfig{
 			Path:           getEnv("UPLOAD_PATH", "./uploads"),
 			MaxFileSize:    maxUploadSize,
 			UploadProvider: getEnv("UPLOAD_PROVIDER", "local"),
 		},
+		Payment: PaymentConfig{
+			Provider:            getEnv("PAYMENT_PROVIDER", "fake"),
+			Currency:            getEnv("PAYMENT_CURRENCY", "USD"),
+			WebhookSecret:       paymentWebhookSecret,
+			AuthorizationWindow: paymentAuthorizationWindow,
+			SweepInterval:       paymentSweepInterval,
+		},
+		Inventory: InventoryConfig{
+			ReservationTTL:           reservationTTL,
+			ReservationSweepInterval: reservationSweepInterval,
+		},
+		Outbox: OutboxConfig{
+			PollInterval:   outboxPollInterval,
+			BatchSize:      outboxBatchSize,
+			RetryBaseDelay: outboxRetryBaseDelay,
+			RetryMaxDelay:  outboxRetryMaxDelay,
+			ClaimTimeout:   outboxClaimTimeout,
+			MaxAttempts:    outboxMaxAttempts,
+			Retention:      outboxRetention,
+			PurgeInterval:  outboxPurgeInterval,
+		},
+		Events: EventsConfig{
+			Publisher: getEnv("EVENT_PUBLISHER", "sqs"),
+		},
+		Webhook: WebhookConfig{
+			DeliveryInterval: webhookDeliveryInterval,
+			BatchSize:        webhookBatchSize,
+			Timeout:          webhookTimeout,
+			MaxAttempts:      webhookMaxAttempts,
+			RetryBaseDelay:   webhookRetryBaseDelay,
+			RetryMaxDelay:    webhookRetryMaxDelay,
+		},
+		Mail: MailConfig{
+			Transport:    getEnv("MAIL_TRANSPORT", "file"),
+			From:         getEnv("MAIL_FROM", "E-Commerce Shop <no-reply@ecommerce.local>"),
+			FilePath:     getEnv("MAIL_FILE_PATH", "./mail"),
+			SMTPHost:     getEnv("SMTP_HOST", "localhost"),
+			SMTPPort:     getEnv("SMTP_PORT", "1025"),
+			SMTPUsername: getEnv("SMTP_USERNAME", ""),
+			SMTPPassword: getEnv("SMTP_PASSWORD", ""),
+		},
+		Worker: WorkerConfig{
+			MaxRetries:           workerMaxRetries,
+			RetryInitialInterval: workerRetryInitialInterval,
+			RetryMaxInterval:     workerRetryMaxInterval,
+		},
 	}, nil
 }
 
@@ -110,3 +433,25 @@ func getEnv(key, defaultValue string) string {
 
 	return defaultValue
 }
+
+// requireEnv reads a setting that has no safe default, such as a secret
+func requireEnv(key string) (string, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return "", fmt.Errorf("%s must be set", key)
+	}
+
+	return value, nil
+}
+
+// getEnvList reads a comma separated list, ignoring empty entries
+func getEnvList(key string) []string {
+	var values []string
+	for value := range strings.SplitSeq(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+
+	return values
+}
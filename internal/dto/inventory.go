@@ -0,0 +1,32 @@
+package dto
+
+import "time"
+
+type AdjustInventoryRequest struct {
+	Delta       int    `json:"delta" binding:"required"`
+	Reason      string `json:"reason" binding:"required,oneof=manual_adjustment return import"`
+	ReferenceID string `json:"reference_id" binding:"max=100"`
+	Note        string `json:"note"`
+}
+
+type InventoryMovementResponse struct {
+	ID          uint      `json:"id"`
+	ProductID   uint      `json:"product_id"`
+	Delta       int       `json:"delta"`
+	StockAfter  int       `json:"stock_after"`
+	Reason      string    `json:"reason"`
+	ReferenceID string    `json:"reference_id"`
+	ActorID     *uint     `json:"actor_id"`
+	Note        string    `json:"note"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type LowStockProductResponse struct {
+	ID                uint   `json:"id"`
+	Name              string `json:"name"`
+	SKU               string `json:"sku"`
+	Stock             int    `json:"stock"`
+	Available         int    `json:"available"`
+	LowStockThreshold int    `json:"low_stock_threshold"`
+	OutOfStock        bool   `json:"out_of_stock"`
+}
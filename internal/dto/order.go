@@ -29,13 +29,37 @@ type CartItemResponse struct {
 	UpdatedAt time.Time       `json:"updated_at"`
 }
 
+type CreateOrderRequest struct {
+	PaymentToken string `json:"payment_token" binding:"required"`
+}
+
+type UpdateOrderStatusRequest struct {
+	Status string `json:"status" binding:"required,oneof=pending confirmed shipped delivered cancelled"`
+	Reason string `json:"reason"`
+}
+
+type CheckoutResponse struct {
+	Reservations []StockReservationResponse `json:"reservations"`
+	ExpiresAt    time.Time                  `json:"expires_at"`
+}
+
+type StockReservationResponse struct {
+	ID          uint      `json:"id"`
+	ProductID   uint      `json:"product_id"`
+	ProductName string    `json:"product_name"`
+	Quantity    int       `json:"quantity"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
 type OrderResponse struct {
-	ID          uint                `json:"id"`
-	UserID      uint                `json:"user_id"`
-	Status      string              `json:"status"`
-	TotalAmount float64             `json:"total_amount"`
-	OrderItems  []OrderItemResponse `json:"order_items"`
-	CreatedAt   string              `json:"created_at"`
+	ID            uint                         `json:"id"`
+	UserID        uint                         `json:"user_id"`
+	Status        string                       `json:"status"`
+	TotalAmount   float64                      `json:"total_amount"`
+	OrderItems    []OrderItemResponse          `json:"order_items"`
+	StatusHistory []OrderStatusHistoryResponse `json:"status_history"`
+	Payment       *PaymentResponse             `json:"payment"`
+	CreatedAt     string                       `json:"created_at"`
 	// CreatedAt   time.Time           `json:"created_at"`
 	// UpdatedAt   time.Time           `json:"updated_at"`
 }
@@ -47,3 +71,21 @@ type OrderItemResponse struct {
 	Price    float64         `json:"price"`
 	// CreatedAt time.Time       `json:"created_at"`
 }
+
+type OrderStatusHistoryResponse struct {
+	ID         uint   `json:"id"`
+	FromStatus string `json:"from_status,omitempty"`
+	ToStatus   string `json:"to_status"`
+	ActorID    *uint  `json:"actor_id"`
+	Reason     string `json:"reason"`
+	CreatedAt  string `json:"created_at"`
+}
+
+type PaymentResponse struct {
+	ID            uint    `json:"id"`
+	Provider      string  `json:"provider"`
+	Amount        float64 `json:"amount"`
+	Currency      string  `json:"currency"`
+	Status        string  `json:"status"`
+	FailureReason string  `json:"failure_reason,omitempty"`
+}
@@ -23,21 +23,23 @@ type CategoryResponse struct {
 }
 
 type CreateProductRequest struct {
-	CategoryID  uint    `json:"category_id" binding:"required"`
-	Name        string  `json:"name" binding:"required"`
-	Description string  `json:"description"`
-	Price       float64 `json:"price" binding:"required,gt=0"`
-	Stock       int     `json:"stock" binding:"min=0"`
-	SKU         string  `json:"sku" binding:"required"`
+	CategoryID        uint    `json:"category_id" binding:"required"`
+	Name              string  `json:"name" binding:"required"`
+	Description       string  `json:"description"`
+	Price             float64 `json:"price" binding:"required,gt=0"`
+	Stock             int     `json:"stock" binding:"min=0"`
+	SKU               string  `json:"sku" binding:"required"`
+	LowStockThreshold *int    `json:"low_stock_threshold" binding:"omitempty,min=0"`
 }
 
 type UpdateProductRequest struct {
-	CategoryID  uint    `json:"category_id" binding:"required"`
-	Name        string  `json:"name" binding:"required"`
-	Description string  `json:"description"`
-	Price       float64 `json:"price" binding:"required,gt=0"`
-	Stock       int     `json:"stock" binding:"min=0"`
-	IsActive    *bool   `json:"is_active"`
+	CategoryID        uint    `json:"category_id" binding:"required"`
+	Name              string  `json:"name" binding:"required"`
+	Description       string  `json:"description"`
+	Price             float64 `json:"price" binding:"required,gt=0"`
+	Stock             int     `json:"stock" binding:"min=0"`
+	IsActive          *bool   `json:"is_active"`
+	LowStockThreshold *int    `json:"low_stock_threshold" binding:"omitempty,min=0"`
 }
 
 type ProductResponse struct {
@@ -47,6 +49,7 @@ type ProductResponse struct {
 	Description string                 `json:"description"`
 	Price       float64                `json:"price"`
 	Stock       int                    `json:"stock"`
+	Available   int                    `json:"available"`
 	SKU         string                 `json:"sku"`
 	IsActive    bool                   `json:"is_active"`
 	Category    CategoryResponse       `json:"category"`
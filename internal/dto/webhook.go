@@ -0,0 +1,52 @@
+package dto
+
+import "time"
+
+type CreateWebhookSubscriptionRequest struct {
+	URL         string   `json:"url" binding:"required,url,max=2048"`
+	EventTypes  []string `json:"event_types" binding:"required,min=1,dive,required"`
+	Description string   `json:"description"`
+}
+
+type UpdateWebhookSubscriptionRequest struct {
+	URL         string   `json:"url" binding:"required,url,max=2048"`
+	EventTypes  []string `json:"event_types" binding:"required,min=1,dive,required"`
+	Description string   `json:"description"`
+	IsActive    *bool    `json:"is_active"`
+}
+
+type WebhookSubscriptionResponse struct {
+	ID          uint     `json:"id"`
+	URL         string   `json:"url"`
+	EventTypes  []string `json:"event_types"`
+	Description string   `json:"description"`
+	IsActive    bool     `json:"is_active"`
+	// Secret is only returned when the subscription is created
+	Secret    string    `json:"secret,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type WebhookDeliveryResponse struct {
+	ID             uint64                           `json:"id"`
+	SubscriptionID uint                             `json:"subscription_id"`
+	EventID        string                           `json:"event_id"`
+	EventType      string                           `json:"event_type"`
+	Status         string                           `json:"status"`
+	AttemptCount   int                              `json:"attempt_count"`
+	LastStatusCode *int                             `json:"last_status_code"`
+	LastError      string                           `json:"last_error"`
+	NextAttemptAt  time.Time                        `json:"next_attempt_at"`
+	DeliveredAt    *time.Time                       `json:"delivered_at"`
+	CreatedAt      time.Time                        `json:"created_at"`
+	Attempts       []WebhookDeliveryAttemptResponse `json:"attempts,omitempty"`
+}
+
+type WebhookDeliveryAttemptResponse struct {
+	ID           uint64    `json:"id"`
+	StatusCode   *int      `json:"status_code"`
+	Error        string    `json:"error"`
+	ResponseBody string    `json:"response_body"`
+	DurationMs   int       `json:"duration_ms"`
+	CreatedAt    time.Time `json:"created_at"`
+}
@@ -0,0 +1,67 @@
+package events
+
+import (
+	"sync"
+
+	"github.com/tomimandalaputra/e-commerce-go/internal/events/schema"
+)
+
+// MemoryPublisher records every published event in memory instead of sending it
+// anywhere. It is meant for tests that need to inspect published events.
+type MemoryPublisher struct {
+	mu        sync.Mutex
+	published []schema.Envelope
+}
+
+func NewMemoryPublisher() *MemoryPublisher {
+	return &MemoryPublisher{}
+}
+
+func (p *MemoryPublisher) Publish(event schema.Event, metadata schema.Metadata) error {
+	envelope, err := schema.NewEnvelope(event, metadata)
+	if err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.published = append(p.published, *envelope)
+	return nil
+}
+
+// Published returns a copy of the events published so far, oldest first.
+func (p *MemoryPublisher) Published() []schema.Envelope {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	published := make([]schema.Envelope, len(p.published))
+	copy(published, p.published)
+	return published
+}
+
+// PublishedOfType returns the events of one event type published so far.
+func (p *MemoryPublisher) PublishedOfType(eventType string) []schema.Envelope {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var published []schema.Envelope
+	for i := range p.published {
+		if p.published[i].EventType == eventType {
+			published = append(published, p.published[i])
+		}
+	}
+	return published
+}
+
+// Reset forgets every recorded event.
+func (p *MemoryPublisher) Reset() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.published = nil
+}
+
+func (p *MemoryPublisher) Close() error {
+	return nil
+}
@@ -0,0 +1,222 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/rs/zerolog"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	appconfig "github.com/tomimandalaputra/e-commerce-go/internal/config"
+	"github.com/tomimandalaputra/e-commerce-go/internal/events/schema"
+	"github.com/tomimandalaputra/e-commerce-go/internal/models"
+	"github.com/tomimandalaputra/e-commerce-go/internal/utils"
+)
+
+// Enqueue writes an event to the outbox using tx, so it is committed or rolled
+// back together with the business change. The outbox relay publishes it later.
+func Enqueue(tx *gorm.DB, event schema.Event, correlationID string) error {
+	envelope, err := schema.NewEnvelope(event, schema.NewMetadata(correlationID))
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(envelope)
+	if err != nil {
+		return err
+	}
+
+	return tx.Create(&models.OutboxEvent{
+		EventID:       envelope.EventID,
+		EventType:     envelope.EventType,
+		Envelope:      string(data),
+		NextAttemptAt: time.Now(),
+	}).Error
+}
+
+// OutboxRelay drains the outbox to a Publisher. Events that fail to publish
+// are retried with exponential backoff and marked dead once they have used up
+// the configured number of attempts.
+type OutboxRelay struct {
+	db        *gorm.DB
+	publisher Publisher
+	config    *appconfig.OutboxConfig
+	logger    *zerolog.Logger
+}
+
+func NewOutboxRelay(db *gorm.DB, publisher Publisher, cfg *appconfig.OutboxConfig, logger *zerolog.Logger) *OutboxRelay {
+	return &OutboxRelay{
+		db:        db,
+		publisher: publisher,
+		config:    cfg,
+		logger:    logger,
+	}
+}
+
+// Start relays pending events every poll interval until ctx is cancelled.
+func (r *OutboxRelay) Start(ctx context.Context) {
+	ticker := time.NewTicker(r.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			// Keep draining while full batches come back so a backlog clears quickly
+			for ctx.Err() == nil {
+				relayed, err := r.RelayPending()
+				if err != nil {
+					r.logger.Error().Err(err).Msg("failed to relay outbox events")
+					break
+				}
+
+				if relayed < r.config.BatchSize {
+					break
+				}
+			}
+		}
+	}
+}
+
+// RelayPending publishes one batch of due events and returns how many were picked up.
+// The batch is claimed in a short transaction by pushing next_attempt_at past the
+// claim timeout, so no row locks are held while publishing and several relays can
+// run side by side. A relay that dies mid-batch leaves its rows to be picked up
+// again once the claim expires.
+func (r *OutboxRelay) RelayPending() (int, error) {
+	pending, err := r.claim()
+	if err != nil {
+		return 0, err
+	}
+
+	for i := range pending {
+		if err := r.relay(&pending[i]); err != nil {
+			return len(pending), err
+		}
+	}
+
+	return len(pending), nil
+}
+
+func (r *OutboxRelay) claim() ([]models.OutboxEvent, error) {
+	var pending []models.OutboxEvent
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("published_at IS NULL AND dead_at IS NULL AND next_attempt_at <= ?", now).
+			Order("id ASC").
+			Limit(r.config.BatchSize).
+			Find(&pending).Error; err != nil {
+			return err
+		}
+
+		if len(pending) == 0 {
+			return nil
+		}
+
+		ids := make([]uint64, len(pending))
+		for i, event := range pending {
+			ids[i] = event.ID
+		}
+
+		return tx.Model(&models.OutboxEvent{}).
+			Where("id IN ?", ids).
+			Update("next_attempt_at", now.Add(r.config.ClaimTimeout)).Error
+	})
+
+	return pending, err
+}
+
+func (r *OutboxRelay) relay(event *models.OutboxEvent) error {
+	publishErr := r.publish(event)
+
+	attempts := event.Attempts + 1
+
+	if publishErr == nil {
+		return r.db.Model(event).Updates(map[string]any{
+			"published_at": time.Now(),
+			"attempts":     attempts,
+			"last_error":   nil,
+		}).Error
+	}
+
+	if attempts >= r.config.MaxAttempts {
+		r.logger.Error().Err(publishErr).
+			Uint64("outbox_id", event.ID).
+			Str("event_type", event.EventType).
+			Int("attempts", attempts).
+			Msg("giving up on outbox event")
+
+		return r.db.Model(event).Updates(map[string]any{
+			"attempts":   attempts,
+			"last_error": publishErr.Error(),
+			"dead_at":    time.Now(),
+		}).Error
+	}
+
+	delay := utils.Backoff(attempts, r.config.RetryBaseDelay, r.config.RetryMaxDelay)
+
+	r.logger.Warn().Err(publishErr).
+		Uint64("outbox_id", event.ID).
+		Str("event_type", event.EventType).
+		Int("attempts", attempts).
+		Dur("retry_in", delay).
+		Msg("failed to publish outbox event")
+
+	return r.db.Model(event).Updates(map[string]any{
+		"attempts":        attempts,
+		"last_error":      publishErr.Error(),
+		"next_attempt_at": time.Now().Add(delay),
+	}).Error
+}
+
+// publish decodes the stored envelope and publishes it again under the same
+// event ID, so consumers can deduplicate retried events.
+func (r *OutboxRelay) publish(event *models.OutboxEvent) error {
+	var envelope schema.Envelope
+	if err := json.Unmarshal([]byte(event.Envelope), &envelope); err != nil {
+		return err
+	}
+
+	decoded, err := schema.DecodeEvent(&envelope)
+	if err != nil {
+		return err
+	}
+
+	return r.publisher.Publish(decoded, envelope.Metadata())
+}
+
+// PurgePublished deletes events that were published longer ago than the retention period.
+// Dead events are kept so they can be inspected and replayed.
+func (r *OutboxRelay) PurgePublished() (int64, error) {
+	result := r.db.Where("published_at < ?", time.Now().Add(-r.config.Retention)).
+		Delete(&models.OutboxEvent{})
+	return result.RowsAffected, result.Error
+}
+
+// StartPurger purges published events every purge interval until ctx is cancelled.
+func (r *OutboxRelay) StartPurger(ctx context.Context) {
+	ticker := time.NewTicker(r.config.PurgeInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			purged, err := r.PurgePublished()
+			if err != nil {
+				r.logger.Error().Err(err).Msg("failed to purge published outbox events")
+				continue
+			}
+
+			if purged > 0 {
+				r.logger.Info().Int64("purged", purged).Msg("purged published outbox events")
+			}
+		}
+	}
+}
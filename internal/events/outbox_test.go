@@ -0,0 +1,160 @@
+package events
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	appconfig "github.com/tomimandalaputra/e-commerce-go/internal/config"
+	"github.com/tomimandalaputra/e-commerce-go/internal/events/schema"
+	"github.com/tomimandalaputra/e-commerce-go/internal/models"
+)
+
+// testDatabaseDSNEnv names the Postgres database, with every migration
+// applied, that tests needing a real database run against.
+const testDatabaseDSNEnv = "TEST_DATABASE_DSN"
+
+func openTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	dsn := os.Getenv(testDatabaseDSNEnv)
+	if dsn == "" {
+		t.Skipf("%s is not set", testDatabaseDSNEnv)
+	}
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("failed to connect to test database: %v", err)
+	}
+
+	return db
+}
+
+func TestOutboxRelayPublishesEnqueuedEvents(t *testing.T) {
+	db := openTestDB(t)
+	outboxID := enqueueTestEvent(t, db)
+
+	publisher := NewMemoryPublisher()
+	relayAll(t, newTestRelay(db, publisher))
+
+	var stored models.OutboxEvent
+	if err := db.First(&stored, outboxID).Error; err != nil {
+		t.Fatalf("failed to load outbox event: %v", err)
+	}
+
+	if stored.PublishedAt == nil {
+		t.Fatal("event was not marked published")
+	}
+
+	var published []schema.Envelope
+	for _, envelope := range publisher.PublishedOfType(schema.ProductPriceChanged) {
+		if envelope.EventID == stored.EventID {
+			published = append(published, envelope)
+		}
+	}
+
+	if len(published) != 1 {
+		t.Fatalf("event published %d times, want once", len(published))
+	}
+
+	var event schema.ProductPriceChangedV1
+	if err := published[0].Decode(&event); err != nil {
+		t.Fatalf("failed to decode published event: %v", err)
+	}
+	if event.NewPrice != 12.5 {
+		t.Errorf("NewPrice = %v, want 12.5", event.NewPrice)
+	}
+}
+
+func TestOutboxRelayMarksPoisonEventsDead(t *testing.T) {
+	db := openTestDB(t)
+	outboxID := enqueueTestEvent(t, db)
+
+	var stored models.OutboxEvent
+	if err := db.First(&stored, outboxID).Error; err != nil {
+		t.Fatalf("failed to load outbox event: %v", err)
+	}
+
+	publisher := &failingPublisher{MemoryPublisher: NewMemoryPublisher(), eventID: stored.EventID}
+	relayAll(t, newTestRelay(db, publisher))
+
+	if err := db.First(&stored, outboxID).Error; err != nil {
+		t.Fatalf("failed to load outbox event: %v", err)
+	}
+
+	if stored.DeadAt == nil || stored.PublishedAt != nil {
+		t.Errorf("dead_at = %v, published_at = %v, want a dead unpublished event", stored.DeadAt, stored.PublishedAt)
+	}
+	if stored.Attempts != 1 || stored.LastError == "" {
+		t.Errorf("attempts = %d, last_error = %q, want one recorded failure", stored.Attempts, stored.LastError)
+	}
+}
+
+// failingPublisher fails to publish one event and records every other one.
+type failingPublisher struct {
+	*MemoryPublisher
+	eventID string
+}
+
+func (p *failingPublisher) Publish(event schema.Event, metadata schema.Metadata) error {
+	if metadata.EventID == p.eventID {
+		return errors.New("queue unavailable")
+	}
+	return p.MemoryPublisher.Publish(event, metadata)
+}
+
+func newTestRelay(db *gorm.DB, publisher Publisher) *OutboxRelay {
+	logger := zerolog.Nop()
+
+	return NewOutboxRelay(db, publisher, &appconfig.OutboxConfig{
+		PollInterval:   time.Second,
+		BatchSize:      100,
+		RetryBaseDelay: time.Second,
+		RetryMaxDelay:  time.Minute,
+		ClaimTimeout:   time.Minute,
+		MaxAttempts:    1,
+		Retention:      time.Hour,
+		PurgeInterval:  time.Hour,
+	}, &logger)
+}
+
+// relayAll relays until the outbox has no due events left
+func relayAll(t *testing.T, relay *OutboxRelay) {
+	t.Helper()
+
+	for {
+		relayed, err := relay.RelayPending()
+		if err != nil {
+			t.Fatalf("RelayPending() error = %v", err)
+		}
+		if relayed < relay.config.BatchSize {
+			return
+		}
+	}
+}
+
+// enqueueTestEvent writes a price change to the outbox and deletes it when the test ends
+func enqueueTestEvent(t *testing.T, db *gorm.DB) uint64 {
+	t.Helper()
+
+	if err := Enqueue(db, schema.ProductPriceChangedV1{ProductID: 1, OldPrice: 10, NewPrice: 12.5}, ""); err != nil {
+		t.Fatalf("Enqueue() error = %v", err)
+	}
+
+	var stored models.OutboxEvent
+	if err := db.Where("event_type = ?", schema.ProductPriceChanged).Order("id DESC").First(&stored).Error; err != nil {
+		t.Fatalf("failed to load outbox event: %v", err)
+	}
+
+	t.Cleanup(func() {
+		db.Delete(&models.OutboxEvent{}, stored.ID)
+	})
+
+	return stored.ID
+}
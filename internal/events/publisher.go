@@ -1,6 +1,10 @@
 package events
 
+import "github.com/tomimandalaputra/e-commerce-go/internal/events/schema"
+
+// Publisher sends typed events. The envelope is built from the event itself,
+// so callers cannot publish a payload that disagrees with its event type.
 type Publisher interface {
-	Publish(eventType string, payload any, metadata map[string]string) error
+	Publish(event schema.Event, metadata schema.Metadata) error
 	Close() error
 }
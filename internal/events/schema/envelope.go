@@ -0,0 +1,194 @@
+// Package schema defines the versioned domain events exchanged over the event
+// queue and the envelope every event travels in.
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Event is implemented by every typed domain event. A breaking change to an
+// event's payload gets a new struct with a higher SchemaVersion.
+type Event interface {
+	EventType() string
+	SchemaVersion() int
+	AggregateID() string
+}
+
+// EventTypes lists every event type published by the application.
+var EventTypes = []string{
+	UserRegistered,
+	UserLoggedIn,
+	UserVerificationRequested,
+	UserEmailVerified,
+	PasswordResetRequested,
+	UserPasswordReset,
+	UserPasswordChanged,
+	RefreshTokenReused,
+	LoginFailed,
+	AccountLocked,
+	UserMFAEnabled,
+	UserMFADisabled,
+	UserRoleAssigned,
+	UserRoleRevoked,
+	OrderCreated,
+	OrderStatusChanged,
+	ProductPriceChanged,
+	ProductLowStock,
+	ProductOutOfStock,
+}
+
+// internalEventTypes carry secrets such as one-time tokens. They are only
+// consumed by the application and never leave it through webhooks or logs.
+var internalEventTypes = map[string]bool{
+	UserVerificationRequested: true,
+	PasswordResetRequested:    true,
+}
+
+// IsInternalEventType reports whether eventType must stay inside the application.
+func IsInternalEventType(eventType string) bool {
+	return internalEventTypes[eventType]
+}
+
+// webhookEventTypes are the commerce events merchants can subscribe to.
+// Account and security events, such as failed logins or role changes, are
+// never sent to webhooks, and new event types stay private until added here.
+var webhookEventTypes = map[string]bool{
+	OrderCreated:        true,
+	OrderStatusChanged:  true,
+	ProductPriceChanged: true,
+	ProductLowStock:     true,
+	ProductOutOfStock:   true,
+}
+
+// IsWebhookEventType reports whether eventType may be delivered to merchant webhooks.
+func IsWebhookEventType(eventType string) bool {
+	return webhookEventTypes[eventType]
+}
+
+// Envelope wraps an event payload with the data consumers need to route,
+// deduplicate and trace it.
+type Envelope struct {
+	EventID       string          `json:"event_id"`
+	EventType     string          `json:"event_type"`
+	SchemaVersion int             `json:"schema_version"`
+	OccurredAt    time.Time       `json:"occurred_at"`
+	CorrelationID string          `json:"correlation_id"`
+	AggregateID   string          `json:"aggregate_id"`
+	Payload       json.RawMessage `json:"payload"`
+}
+
+// Metadata identifies one occurrence of an event. It is assigned once when the
+// event is recorded and kept every time the event is published again.
+type Metadata struct {
+	EventID       string
+	OccurredAt    time.Time
+	CorrelationID string
+}
+
+// NewMetadata identifies a new event occurrence. Events caused by the same
+// operation should share a correlation ID; an empty one starts a new correlation.
+func NewMetadata(correlationID string) Metadata {
+	if correlationID == "" {
+		correlationID = uuid.NewString()
+	}
+
+	return Metadata{
+		EventID:       uuid.NewString(),
+		OccurredAt:    time.Now().UTC(),
+		CorrelationID: correlationID,
+	}
+}
+
+// NewEnvelope wraps an event. The event type, schema version, aggregate ID and
+// payload always come from the typed event, so they cannot disagree.
+func NewEnvelope(event Event, metadata Metadata) (*Envelope, error) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal %s payload: %w", event.EventType(), err)
+	}
+
+	return &Envelope{
+		EventID:       metadata.EventID,
+		EventType:     event.EventType(),
+		SchemaVersion: event.SchemaVersion(),
+		OccurredAt:    metadata.OccurredAt,
+		CorrelationID: metadata.CorrelationID,
+		AggregateID:   event.AggregateID(),
+		Payload:       payload,
+	}, nil
+}
+
+// Metadata returns the occurrence data of the wrapped event.
+func (e *Envelope) Metadata() Metadata {
+	return Metadata{
+		EventID:       e.EventID,
+		OccurredAt:    e.OccurredAt,
+		CorrelationID: e.CorrelationID,
+	}
+}
+
+// Decode unmarshals the payload into event after checking that the envelope
+// carries that event type and schema version.
+func (e *Envelope) Decode(event Event) error {
+	if e.EventType != event.EventType() || e.SchemaVersion != event.SchemaVersion() {
+		return fmt.Errorf("cannot decode %s v%d as %s v%d",
+			e.EventType, e.SchemaVersion, event.EventType(), event.SchemaVersion())
+	}
+
+	return json.Unmarshal(e.Payload, event)
+}
+
+type eventKey struct {
+	eventType     string
+	schemaVersion int
+}
+
+// eventFactories creates an empty event for every event type and schema
+// version, so stored envelopes can be turned back into typed events.
+var eventFactories = map[eventKey]func() Event{
+	{UserRegistered, 1}:            func() Event { return &UserRegisteredV1{} },
+	{UserLoggedIn, 1}:              func() Event { return &UserLoggedInV1{} },
+	{UserLoggedIn, 2}:              func() Event { return &UserLoggedInV2{} },
+	{UserVerificationRequested, 1}: func() Event { return &UserVerificationRequestedV1{} },
+	{UserVerificationRequested, 2}: func() Event { return &UserVerificationRequestedV2{} },
+	{UserEmailVerified, 1}:         func() Event { return &UserEmailVerifiedV1{} },
+	{PasswordResetRequested, 1}:    func() Event { return &PasswordResetRequestedV1{} },
+	{PasswordResetRequested, 2}:    func() Event { return &PasswordResetRequestedV2{} },
+	{UserPasswordReset, 1}:         func() Event { return &UserPasswordResetV1{} },
+	{UserPasswordChanged, 1}:       func() Event { return &UserPasswordChangedV1{} },
+	{RefreshTokenReused, 1}:        func() Event { return &RefreshTokenReusedV1{} },
+	{LoginFailed, 1}:               func() Event { return &LoginFailedV1{} },
+	{AccountLocked, 1}:             func() Event { return &AccountLockedV1{} },
+	{UserMFAEnabled, 1}:            func() Event { return &UserMFAEnabledV1{} },
+	{UserMFADisabled, 1}:           func() Event { return &UserMFADisabledV1{} },
+	{UserRoleAssigned, 1}:          func() Event { return &UserRoleAssignedV1{} },
+	{UserRoleRevoked, 1}:           func() Event { return &UserRoleRevokedV1{} },
+	{OrderCreated, 1}:              func() Event { return &OrderCreatedV1{} },
+	{OrderStatusChanged, 1}:        func() Event { return &OrderStatusChangedV1{} },
+	{ProductPriceChanged, 1}:       func() Event { return &ProductPriceChangedV1{} },
+	{ProductLowStock, 1}:           func() Event { return &ProductLowStockV1{} },
+	{ProductOutOfStock, 1}:         func() Event { return &ProductOutOfStockV1{} },
+}
+
+// DecodeEvent turns an envelope back into the typed event it wraps.
+func DecodeEvent(e *Envelope) (Event, error) {
+	factory, ok := eventFactories[eventKey{e.EventType, e.SchemaVersion}]
+	if !ok {
+		return nil, fmt.Errorf("unknown event %s v%d", e.EventType, e.SchemaVersion)
+	}
+
+	event := factory()
+	if err := e.Decode(event); err != nil {
+		return nil, err
+	}
+
+	return event, nil
+}
+
+func aggregateID(kind string, id uint) string {
+	return fmt.Sprintf("%s:%d", kind, id)
+}
@@ -0,0 +1,66 @@
+package schema
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEveryEventTypeDecodes(t *testing.T) {
+	for _, eventType := range EventTypes {
+		if _, ok := eventFactories[eventKey{eventType, 1}]; !ok {
+			t.Errorf("no factory registered for %s v1", eventType)
+		}
+	}
+}
+
+func TestWebhookEventTypesArePublic(t *testing.T) {
+	for _, eventType := range []string{LoginFailed, AccountLocked, RefreshTokenReused, UserLoggedIn, UserRoleAssigned, UserRoleRevoked, PasswordResetRequested} {
+		if IsWebhookEventType(eventType) {
+			t.Errorf("%s can be delivered to webhooks", eventType)
+		}
+	}
+
+	for eventType := range webhookEventTypes {
+		if IsInternalEventType(eventType) {
+			t.Errorf("internal event %s can be delivered to webhooks", eventType)
+		}
+	}
+}
+
+func TestDecodeEventRoundTrip(t *testing.T) {
+	event := OrderStatusChangedV1{OrderID: 42, FromStatus: "pending", ToStatus: "confirmed", Reason: "paid"}
+	metadata := NewMetadata("")
+
+	envelope, err := NewEnvelope(event, metadata)
+	if err != nil {
+		t.Fatalf("NewEnvelope: %v", err)
+	}
+
+	if envelope.AggregateID != "order:42" {
+		t.Errorf("AggregateID = %q, want order:42", envelope.AggregateID)
+	}
+	if envelope.Metadata() != metadata {
+		t.Errorf("Metadata() = %+v, want %+v", envelope.Metadata(), metadata)
+	}
+
+	decoded, err := DecodeEvent(envelope)
+	if err != nil {
+		t.Fatalf("DecodeEvent: %v", err)
+	}
+
+	if got, ok := decoded.(*OrderStatusChangedV1); !ok || !reflect.DeepEqual(*got, event) {
+		t.Errorf("DecodeEvent = %#v, want %#v", decoded, event)
+	}
+}
+
+func TestDecodeEventUnknownVersion(t *testing.T) {
+	envelope, err := NewEnvelope(UserRegisteredV1{UserID: 1}, NewMetadata(""))
+	if err != nil {
+		t.Fatalf("NewEnvelope: %v", err)
+	}
+
+	envelope.SchemaVersion = 99
+	if _, err := DecodeEvent(envelope); err == nil {
+		t.Error("DecodeEvent accepted an unknown schema version")
+	}
+}
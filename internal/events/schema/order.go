@@ -0,0 +1,41 @@
+package schema
+
+const (
+	// OrderCreated is published when a checkout places a new order.
+	OrderCreated = "ORDER_CREATED"
+	// OrderStatusChanged is published whenever an existing order moves to another status.
+	OrderStatusChanged = "ORDER_STATUS_CHANGED"
+)
+
+// OrderCreatedV1 is the payload of ORDER_CREATED.
+type OrderCreatedV1 struct {
+	OrderID     uint          `json:"order_id"`
+	UserID      uint          `json:"user_id"`
+	Status      string        `json:"status"`
+	TotalAmount float64       `json:"total_amount"`
+	Items       []OrderItemV1 `json:"items"`
+}
+
+// OrderItemV1 is a line of an order in the order events.
+type OrderItemV1 struct {
+	ProductID uint    `json:"product_id"`
+	Quantity  int     `json:"quantity"`
+	Price     float64 `json:"price"`
+}
+
+func (OrderCreatedV1) EventType() string     { return OrderCreated }
+func (OrderCreatedV1) SchemaVersion() int    { return 1 }
+func (e OrderCreatedV1) AggregateID() string { return aggregateID("order", e.OrderID) }
+
+// OrderStatusChangedV1 is the payload of ORDER_STATUS_CHANGED.
+type OrderStatusChangedV1 struct {
+	OrderID    uint   `json:"order_id"`
+	FromStatus string `json:"from_status"`
+	ToStatus   string `json:"to_status"`
+	ActorID    *uint  `json:"actor_id"`
+	Reason     string `json:"reason"`
+}
+
+func (OrderStatusChangedV1) EventType() string     { return OrderStatusChanged }
+func (OrderStatusChangedV1) SchemaVersion() int    { return 1 }
+func (e OrderStatusChangedV1) AggregateID() string { return aggregateID("order", e.OrderID) }
@@ -0,0 +1,51 @@
+package schema
+
+const (
+	// ProductPriceChanged is published when an admin changes a product's price.
+	ProductPriceChanged = "PRODUCT_PRICE_CHANGED"
+	// ProductLowStock is published when a product's stock falls to or below its low-stock threshold.
+	ProductLowStock = "PRODUCT_LOW_STOCK"
+	// ProductOutOfStock is published when a product's stock reaches zero.
+	ProductOutOfStock = "PRODUCT_OUT_OF_STOCK"
+)
+
+// ProductPriceChangedV1 is the payload of PRODUCT_PRICE_CHANGED.
+type ProductPriceChangedV1 struct {
+	ProductID uint    `json:"product_id"`
+	SKU       string  `json:"sku"`
+	OldPrice  float64 `json:"old_price"`
+	NewPrice  float64 `json:"new_price"`
+	ActorID   uint    `json:"actor_id"`
+}
+
+func (ProductPriceChangedV1) EventType() string     { return ProductPriceChanged }
+func (ProductPriceChangedV1) SchemaVersion() int    { return 1 }
+func (e ProductPriceChangedV1) AggregateID() string { return aggregateID("product", e.ProductID) }
+
+// ProductStockAlertV1 is the payload shared by PRODUCT_LOW_STOCK and PRODUCT_OUT_OF_STOCK.
+type ProductStockAlertV1 struct {
+	ProductID         uint   `json:"product_id"`
+	Name              string `json:"name"`
+	SKU               string `json:"sku"`
+	Stock             int    `json:"stock"`
+	LowStockThreshold int    `json:"low_stock_threshold"`
+	OrderID           uint   `json:"order_id"`
+}
+
+// ProductLowStockV1 is the payload of PRODUCT_LOW_STOCK.
+type ProductLowStockV1 struct {
+	ProductStockAlertV1
+}
+
+func (ProductLowStockV1) EventType() string     { return ProductLowStock }
+func (ProductLowStockV1) SchemaVersion() int    { return 1 }
+func (e ProductLowStockV1) AggregateID() string { return aggregateID("product", e.ProductID) }
+
+// ProductOutOfStockV1 is the payload of PRODUCT_OUT_OF_STOCK.
+type ProductOutOfStockV1 struct {
+	ProductStockAlertV1
+}
+
+func (ProductOutOfStockV1) EventType() string     { return ProductOutOfStock }
+func (ProductOutOfStockV1) SchemaVersion() int    { return 1 }
+func (e ProductOutOfStockV1) AggregateID() string { return aggregateID("product", e.ProductID) }
@@ -0,0 +1,246 @@
+package schema
+
+import "time"
+
+const (
+	// UserRegistered is published when a new customer account is created.
+	UserRegistered = "USER_REGISTERED"
+	// UserLoggedIn is published when a user signs in with their password.
+	UserLoggedIn = "USER_LOGGED_IN"
+	// UserVerificationRequested is published when a verification email must be sent.
+	// The token itself stays encrypted in user_tokens; the event is internal all the same.
+	UserVerificationRequested = "USER_VERIFICATION_REQUESTED"
+	// UserEmailVerified is published when a user confirms their email address.
+	UserEmailVerified = "USER_EMAIL_VERIFIED"
+	// PasswordResetRequested is published when a password reset link must be sent.
+	// The token itself stays encrypted in password_reset_tokens; the event is internal all the same.
+	PasswordResetRequested = "PASSWORD_RESET_REQUESTED"
+	// UserPasswordReset is published when a user sets a new password with a reset token.
+	UserPasswordReset = "USER_PASSWORD_RESET"
+	// UserPasswordChanged is published when a signed-in user changes their password.
+	UserPasswordChanged = "USER_PASSWORD_CHANGED"
+	// RefreshTokenReused is published when a rotated refresh token is presented
+	// again, a sign it was stolen. Its whole family has been revoked.
+	RefreshTokenReused = "REFRESH_TOKEN_REUSED"
+	// LoginFailed is published when a wrong password or MFA code counts against an account.
+	LoginFailed = "LOGIN_FAILED"
+	// AccountLocked is published when too many failed logins lock an account.
+	AccountLocked = "ACCOUNT_LOCKED"
+	// UserMFAEnabled is published when a user turns on two-factor authentication.
+	UserMFAEnabled = "USER_MFA_ENABLED"
+	// UserMFADisabled is published when a user turns off two-factor authentication.
+	UserMFADisabled = "USER_MFA_DISABLED"
+	// UserRoleAssigned is published when a staff role is granted to a user.
+	UserRoleAssigned = "USER_ROLE_ASSIGNED"
+	// UserRoleRevoked is published when a staff role is taken away from a user.
+	UserRoleRevoked = "USER_ROLE_REVOKED"
+)
+
+// UserRegisteredV1 is the payload of USER_REGISTERED.
+type UserRegisteredV1 struct {
+	UserID    uint   `json:"user_id"`
+	Email     string `json:"email"`
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+}
+
+func (UserRegisteredV1) EventType() string     { return UserRegistered }
+func (UserRegisteredV1) SchemaVersion() int    { return 1 }
+func (e UserRegisteredV1) AggregateID() string { return aggregateID("user", e.UserID) }
+
+// UserLoggedInV1 is the payload of USER_LOGGED_IN. Role is the legacy
+// admin/customer flag, which no longer exists; see UserLoggedInV2.
+type UserLoggedInV1 struct {
+	UserID uint   `json:"user_id"`
+	Email  string `json:"email"`
+	Role   string `json:"role"`
+}
+
+func (UserLoggedInV1) EventType() string     { return UserLoggedIn }
+func (UserLoggedInV1) SchemaVersion() int    { return 1 }
+func (e UserLoggedInV1) AggregateID() string { return aggregateID("user", e.UserID) }
+
+// UserLoggedInV2 is the payload of USER_LOGGED_IN. What a user may do is
+// decided by their assigned roles, which are published as USER_ROLE_ASSIGNED.
+type UserLoggedInV2 struct {
+	UserID uint   `json:"user_id"`
+	Email  string `json:"email"`
+}
+
+func (UserLoggedInV2) EventType() string     { return UserLoggedIn }
+func (UserLoggedInV2) SchemaVersion() int    { return 2 }
+func (e UserLoggedInV2) AggregateID() string { return aggregateID("user", e.UserID) }
+
+// UserVerificationRequestedV1 is the payload of USER_VERIFICATION_REQUESTED.
+// It is only decoded to deliver events enqueued before V2.
+type UserVerificationRequestedV1 struct {
+	UserID    uint      `json:"user_id"`
+	Email     string    `json:"email"`
+	FirstName string    `json:"first_name"`
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+func (UserVerificationRequestedV1) EventType() string     { return UserVerificationRequested }
+func (UserVerificationRequestedV1) SchemaVersion() int    { return 1 }
+func (e UserVerificationRequestedV1) AggregateID() string { return aggregateID("user", e.UserID) }
+
+// UserVerificationRequestedV2 is the payload of USER_VERIFICATION_REQUESTED.
+// Unlike V1 it refers to the token instead of carrying it, so the raw token
+// never reaches the outbox or the event queue.
+type UserVerificationRequestedV2 struct {
+	UserID    uint      `json:"user_id"`
+	Email     string    `json:"email"`
+	FirstName string    `json:"first_name"`
+	TokenID   uint      `json:"token_id"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+func (UserVerificationRequestedV2) EventType() string     { return UserVerificationRequested }
+func (UserVerificationRequestedV2) SchemaVersion() int    { return 2 }
+func (e UserVerificationRequestedV2) AggregateID() string { return aggregateID("user", e.UserID) }
+
+// UserEmailVerifiedV1 is the payload of USER_EMAIL_VERIFIED.
+type UserEmailVerifiedV1 struct {
+	UserID uint   `json:"user_id"`
+	Email  string `json:"email"`
+}
+
+func (UserEmailVerifiedV1) EventType() string     { return UserEmailVerified }
+func (UserEmailVerifiedV1) SchemaVersion() int    { return 1 }
+func (e UserEmailVerifiedV1) AggregateID() string { return aggregateID("user", e.UserID) }
+
+// PasswordResetRequestedV1 is the payload of PASSWORD_RESET_REQUESTED.
+// It is only decoded to deliver events enqueued before V2.
+type PasswordResetRequestedV1 struct {
+	UserID    uint      `json:"user_id"`
+	Email     string    `json:"email"`
+	FirstName string    `json:"first_name"`
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+func (PasswordResetRequestedV1) EventType() string     { return PasswordResetRequested }
+func (PasswordResetRequestedV1) SchemaVersion() int    { return 1 }
+func (e PasswordResetRequestedV1) AggregateID() string { return aggregateID("user", e.UserID) }
+
+// PasswordResetRequestedV2 is the payload of PASSWORD_RESET_REQUESTED.
+// Unlike V1 it refers to the token instead of carrying it, so the raw token
+// never reaches the outbox or the event queue.
+type PasswordResetRequestedV2 struct {
+	UserID    uint      `json:"user_id"`
+	Email     string    `json:"email"`
+	FirstName string    `json:"first_name"`
+	TokenID   uint      `json:"token_id"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+func (PasswordResetRequestedV2) EventType() string     { return PasswordResetRequested }
+func (PasswordResetRequestedV2) SchemaVersion() int    { return 2 }
+func (e PasswordResetRequestedV2) AggregateID() string { return aggregateID("user", e.UserID) }
+
+// UserPasswordResetV1 is the payload of USER_PASSWORD_RESET.
+type UserPasswordResetV1 struct {
+	UserID uint   `json:"user_id"`
+	Email  string `json:"email"`
+}
+
+func (UserPasswordResetV1) EventType() string     { return UserPasswordReset }
+func (UserPasswordResetV1) SchemaVersion() int    { return 1 }
+func (e UserPasswordResetV1) AggregateID() string { return aggregateID("user", e.UserID) }
+
+// UserPasswordChangedV1 is the payload of USER_PASSWORD_CHANGED.
+type UserPasswordChangedV1 struct {
+	UserID uint   `json:"user_id"`
+	Email  string `json:"email"`
+}
+
+func (UserPasswordChangedV1) EventType() string     { return UserPasswordChanged }
+func (UserPasswordChangedV1) SchemaVersion() int    { return 1 }
+func (e UserPasswordChangedV1) AggregateID() string { return aggregateID("user", e.UserID) }
+
+// RefreshTokenReusedV1 is the payload of REFRESH_TOKEN_REUSED.
+type RefreshTokenReusedV1 struct {
+	UserID    uint   `json:"user_id"`
+	FamilyID  string `json:"family_id"`
+	TokenID   uint   `json:"token_id"`
+	UserAgent string `json:"user_agent"`
+	IPAddress string `json:"ip_address"`
+}
+
+func (RefreshTokenReusedV1) EventType() string     { return RefreshTokenReused }
+func (RefreshTokenReusedV1) SchemaVersion() int    { return 1 }
+func (e RefreshTokenReusedV1) AggregateID() string { return aggregateID("user", e.UserID) }
+
+// LoginFailedV1 is the payload of LOGIN_FAILED. UserID was nil in events
+// published for unknown emails, which are now only kept in login_attempts.
+type LoginFailedV1 struct {
+	Email          string `json:"email"`
+	UserID         *uint  `json:"user_id"`
+	IPAddress      string `json:"ip_address"`
+	Reason         string `json:"reason"`
+	FailedAttempts int    `json:"failed_attempts"`
+}
+
+func (LoginFailedV1) EventType() string  { return LoginFailed }
+func (LoginFailedV1) SchemaVersion() int { return 1 }
+func (e LoginFailedV1) AggregateID() string {
+	if e.UserID == nil {
+		return "email:" + e.Email
+	}
+	return aggregateID("user", *e.UserID)
+}
+
+// AccountLockedV1 is the payload of ACCOUNT_LOCKED.
+type AccountLockedV1 struct {
+	UserID      uint      `json:"user_id"`
+	Email       string    `json:"email"`
+	IPAddress   string    `json:"ip_address"`
+	LockedUntil time.Time `json:"locked_until"`
+}
+
+func (AccountLockedV1) EventType() string     { return AccountLocked }
+func (AccountLockedV1) SchemaVersion() int    { return 1 }
+func (e AccountLockedV1) AggregateID() string { return aggregateID("user", e.UserID) }
+
+// UserMFAEnabledV1 is the payload of USER_MFA_ENABLED.
+type UserMFAEnabledV1 struct {
+	UserID uint   `json:"user_id"`
+	Email  string `json:"email"`
+}
+
+func (UserMFAEnabledV1) EventType() string     { return UserMFAEnabled }
+func (UserMFAEnabledV1) SchemaVersion() int    { return 1 }
+func (e UserMFAEnabledV1) AggregateID() string { return aggregateID("user", e.UserID) }
+
+// UserMFADisabledV1 is the payload of USER_MFA_DISABLED.
+type UserMFADisabledV1 struct {
+	UserID uint   `json:"user_id"`
+	Email  string `json:"email"`
+}
+
+func (UserMFADisabledV1) EventType() string     { return UserMFADisabled }
+func (UserMFADisabledV1) SchemaVersion() int    { return 1 }
+func (e UserMFADisabledV1) AggregateID() string { return aggregateID("user", e.UserID) }
+
+// UserRoleAssignedV1 is the payload of USER_ROLE_ASSIGNED.
+type UserRoleAssignedV1 struct {
+	UserID  uint   `json:"user_id"`
+	Role    string `json:"role"`
+	ActorID uint   `json:"actor_id"`
+}
+
+func (UserRoleAssignedV1) EventType() string     { return UserRoleAssigned }
+func (UserRoleAssignedV1) SchemaVersion() int    { return 1 }
+func (e UserRoleAssignedV1) AggregateID() string { return aggregateID("user", e.UserID) }
+
+// UserRoleRevokedV1 is the payload of USER_ROLE_REVOKED.
+type UserRoleRevokedV1 struct {
+	UserID  uint   `json:"user_id"`
+	Role    string `json:"role"`
+	ActorID uint   `json:"actor_id"`
+}
+
+func (UserRoleRevokedV1) EventType() string     { return UserRoleRevoked }
+func (UserRoleRevokedV1) SchemaVersion() int    { return 1 }
+func (e UserRoleRevokedV1) AggregateID() string { return aggregateID("user", e.UserID) }
@@ -4,14 +4,17 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strconv"
 
 	"github.com/ThreeDotsLabs/watermill"
 	"github.com/ThreeDotsLabs/watermill-aws/sqs"
 	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/ThreeDotsLabs/watermill/pubsub/gochannel"
 
 	_ "github.com/aws/smithy-go/endpoints"
 
 	appconfig "github.com/tomimandalaputra/e-commerce-go/internal/config"
+	"github.com/tomimandalaputra/e-commerce-go/internal/events/schema"
 	"github.com/tomimandalaputra/e-commerce-go/internal/providers"
 )
 
@@ -20,20 +23,23 @@ type EventPublisher struct {
 	queueName string
 }
 
-func (ep *EventPublisher) Publish(eventType string, payload any, metadata map[string]string) error {
+func (ep *EventPublisher) Publish(event schema.Event, metadata schema.Metadata) error {
+	envelope, err := schema.NewEnvelope(event, metadata)
+	if err != nil {
+		return err
+	}
 
-	data, err := json.Marshal(payload)
+	data, err := json.Marshal(envelope)
 	if err != nil {
 		return err
 	}
 
-	msg := message.NewMessage(watermill.NewUUID(), data)
+	msg := message.NewMessage(envelope.EventID, data)
 
-	// Add metadata
-	msg.Metadata.Set("event_type", eventType)
-	for k, v := range metadata {
-		msg.Metadata.Set(k, v)
-	}
+	// Add metadata so consumers can route without decoding the body
+	msg.Metadata.Set("event_type", envelope.EventType)
+	msg.Metadata.Set("schema_version", strconv.Itoa(envelope.SchemaVersion))
+	msg.Metadata.Set("correlation_id", envelope.CorrelationID)
 
 	return ep.publisher.Publish(ep.queueName, msg)
 }
@@ -43,6 +49,87 @@ func (ep *EventPublisher) Close() error {
 }
 
 func NewEventPublisher(ctx context.Context, cfg *appconfig.AWSConfig) (*EventPublisher, error) {
+	publisher, err := newSQSPublisher(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &EventPublisher{
+		publisher: publisher,
+		queueName: cfg.EventQueueName,
+	}, nil
+}
+
+// Transport connects the outbox relay to the worker: events published with
+// Publisher are consumed from Subscriber, and events the worker gives up on are
+// moved to the dead-letter queue with DeadLetterPublisher.
+type Transport struct {
+	Publisher           *EventPublisher
+	Subscriber          message.Subscriber
+	DeadLetterPublisher message.Publisher
+}
+
+// NewSQSTransport creates a transport backed by the SQS event and dead-letter queues.
+func NewSQSTransport(ctx context.Context, cfg *appconfig.AWSConfig) (*Transport, error) {
+	publisher, err := NewEventPublisher(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	subscriber, err := NewEventSubscriber(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	deadLetterPublisher, err := newSQSPublisher(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Transport{
+		Publisher:           publisher,
+		Subscriber:          subscriber,
+		DeadLetterPublisher: deadLetterPublisher,
+	}, nil
+}
+
+// NewGoChannelTransport creates a transport backed by an in-process Go channel,
+// so the worker can run without a queue. The relay and the handlers must run in
+// the same process, and events still in flight are lost when it stops.
+func NewGoChannelTransport(cfg *appconfig.AWSConfig) *Transport {
+	logger := watermill.NewStdLogger(false, false)
+	pubSub := gochannel.NewGoChannel(gochannel.Config{}, logger)
+
+	return &Transport{
+		Publisher: &EventPublisher{
+			publisher: pubSub,
+			queueName: cfg.EventQueueName,
+		},
+		Subscriber:          pubSub,
+		DeadLetterPublisher: pubSub,
+	}
+}
+
+// NewEventSubscriber creates an SQS subscriber for the event queue.
+func NewEventSubscriber(ctx context.Context, cfg *appconfig.AWSConfig) (*sqs.Subscriber, error) {
+	logger := watermill.NewStdLogger(false, false)
+
+	awsConfig, err := providers.CreateAWSConfig(ctx, cfg.SQSEndpoint, cfg.Region, cfg.AccessKeyID, cfg.SecretAccessKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create AWS config: %w", err)
+	}
+
+	subscriber, err := sqs.NewSubscriber(sqs.SubscriberConfig{
+		AWSConfig: awsConfig,
+	}, logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create subscriber: %w", err)
+	}
+
+	return subscriber, nil
+}
+
+func newSQSPublisher(ctx context.Context, cfg *appconfig.AWSConfig) (*sqs.Publisher, error) {
 	// Debug mode enabled (true, true) to see events in your terminal
 	logger := watermill.NewStdLogger(false, false)
 
@@ -64,8 +151,5 @@ func NewEventPublisher(ctx context.Context, cfg *appconfig.AWSConfig) (*EventPub
 		return nil, fmt.Errorf("failed to create publisher: %w", err)
 	}
 
-	return &EventPublisher{
-		publisher: publisher,
-		queueName: cfg.EventQueueName,
-	}, nil
+	return publisher, nil
 }
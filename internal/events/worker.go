@@ -0,0 +1,127 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill"
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/ThreeDotsLabs/watermill/message/router/middleware"
+	"github.com/rs/zerolog"
+
+	appconfig "github.com/tomimandalaputra/e-commerce-go/internal/config"
+	"github.com/tomimandalaputra/e-commerce-go/internal/events/schema"
+)
+
+// HandlerFunc handles a single event. Returning an error retries the event and,
+// once retries are exhausted, moves it to the dead-letter queue.
+type HandlerFunc func(envelope *schema.Envelope) error
+
+// Registry routes consumed events to their handlers based on the event_type metadata.
+type Registry struct {
+	handlers map[string][]HandlerFunc
+	logger   *zerolog.Logger
+}
+
+func NewRegistry(logger *zerolog.Logger) *Registry {
+	return &Registry{
+		handlers: make(map[string][]HandlerFunc),
+		logger:   logger,
+	}
+}
+
+// Register adds a handler for an event type. Handlers run in registration
+// order, and a retried event runs all of them again, so they must be idempotent.
+func (r *Registry) Register(eventType string, handler HandlerFunc) {
+	r.handlers[eventType] = append(r.handlers[eventType], handler)
+}
+
+// Handle dispatches a message to the handlers of its event type. Events nobody
+// handles are acknowledged so they do not block the queue.
+func (r *Registry) Handle(msg *message.Message) error {
+	eventType := msg.Metadata.Get("event_type")
+
+	handlers, ok := r.handlers[eventType]
+	if !ok {
+		r.logger.Warn().
+			Str("message_id", msg.UUID).
+			Str("event_type", eventType).
+			Msg("no handler registered for event, skipping")
+		return nil
+	}
+
+	var envelope schema.Envelope
+	if err := json.Unmarshal(msg.Payload, &envelope); err != nil {
+		return fmt.Errorf("invalid event envelope: %w", err)
+	}
+
+	for _, handler := range handlers {
+		if err := handler(&envelope); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Worker consumes the event queue and dispatches every message through a Registry.
+type Worker struct {
+	router              *message.Router
+	deadLetterPublisher message.Publisher
+}
+
+// NewWorker creates a worker subscribed to the event queue of transport. Failed
+// messages are retried with backoff and then published to the dead-letter queue.
+func NewWorker(transport *Transport, awsCfg *appconfig.AWSConfig, workerCfg *appconfig.WorkerConfig, registry *Registry) (*Worker, error) {
+	logger := watermill.NewStdLogger(false, false)
+
+	router, err := message.NewRouter(message.RouterConfig{CloseTimeout: 15 * time.Second}, logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create router: %w", err)
+	}
+
+	poisonQueue, err := middleware.PoisonQueue(transport.DeadLetterPublisher, awsCfg.DeadLetterQueueName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create poison queue middleware: %w", err)
+	}
+
+	// Middlewares run outermost first: panics become errors, errors are
+	// retried, and whatever still fails is moved to the dead-letter queue
+	router.AddMiddleware(
+		poisonQueue,
+		middleware.Retry{
+			MaxRetries:      workerCfg.MaxRetries,
+			InitialInterval: workerCfg.RetryInitialInterval,
+			MaxInterval:     workerCfg.RetryMaxInterval,
+			Multiplier:      2,
+			Logger:          logger,
+		}.Middleware,
+		middleware.Recoverer,
+	)
+
+	router.AddConsumerHandler("event_registry", awsCfg.EventQueueName, transport.Subscriber, registry.Handle)
+
+	return &Worker{
+		router:              router,
+		deadLetterPublisher: transport.DeadLetterPublisher,
+	}, nil
+}
+
+// Run consumes events until the worker is closed or ctx is cancelled.
+func (w *Worker) Run(ctx context.Context) error {
+	defer w.deadLetterPublisher.Close()
+
+	return w.router.Run(ctx)
+}
+
+// Running is closed once the worker has started consuming.
+func (w *Worker) Running() chan struct{} {
+	return w.router.Running()
+}
+
+// Close stops consuming and waits for in-flight messages to finish.
+func (w *Worker) Close() error {
+	return w.router.Close()
+}
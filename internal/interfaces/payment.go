@@ -0,0 +1,38 @@
+package interfaces
+
+type PaymentProvider interface {
+	Name() string
+	Authorize(req *PaymentRequest) (*PaymentResult, error)
+	Capture(providerPaymentID string, amount float64) (*PaymentResult, error)
+	Refund(providerPaymentID string, amount float64) (*PaymentResult, error)
+	Void(providerPaymentID string) (*PaymentResult, error)
+	ParseWebhook(payload []byte) (*PaymentWebhookEvent, error)
+}
+
+type PaymentRequest struct {
+	OrderID  uint
+	Amount   float64
+	Currency string
+	// Token is the tokenized card or payment method supplied by the client.
+	Token string
+}
+
+type PaymentResult struct {
+	ProviderPaymentID string
+	Approved          bool
+	DeclineReason     string
+}
+
+// Payment webhook event types understood by the order workflow.
+const (
+	PaymentEventSucceeded = "payment.succeeded"
+	PaymentEventFailed    = "payment.failed"
+)
+
+type PaymentWebhookEvent struct {
+	// EventID is the provider's unique ID for the notification, used to ignore replays.
+	EventID           string
+	Type              string
+	ProviderPaymentID string
+	FailureReason     string
+}
@@ -0,0 +1,61 @@
+package models
+
+import "time"
+
+// StockReservation holds product stock for a user while they complete checkout.
+// Active reservations count against the stock available to other customers
+// until they are consumed by an order or released when they expire.
+type StockReservation struct {
+	ID        uint              `json:"id" gorm:"primaryKey"`
+	UserID    uint              `json:"user_id" gorm:"not null"`
+	ProductID uint              `json:"product_id" gorm:"not null"`
+	OrderID   *uint             `json:"order_id"`
+	Quantity  int               `json:"quantity" gorm:"not null"`
+	Status    ReservationStatus `json:"status" gorm:"default:active"`
+	ExpiresAt time.Time         `json:"expires_at" gorm:"not null;index"`
+	CreatedAt time.Time         `json:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at"`
+
+	// Relationships
+	User    User    `json:"-"`
+	Product Product `json:"-"`
+}
+
+// ReservationStatus represents the lifecycle state of a stock reservation.
+type ReservationStatus string
+
+// Reservation status constants.
+const (
+	ReservationStatusActive   ReservationStatus = "active"
+	ReservationStatusConsumed ReservationStatus = "consumed"
+	ReservationStatusReleased ReservationStatus = "released"
+)
+
+// InventoryMovement is a ledger entry recording a single change to a product's stock.
+type InventoryMovement struct {
+	ID          uint                    `json:"id" gorm:"primaryKey"`
+	ProductID   uint                    `json:"product_id" gorm:"not null;index"`
+	Delta       int                     `json:"delta" gorm:"not null"`
+	StockAfter  int                     `json:"stock_after" gorm:"not null"`
+	Reason      InventoryMovementReason `json:"reason" gorm:"not null"`
+	ReferenceID string                  `json:"reference_id"`
+	ActorID     *uint                   `json:"actor_id"`
+	Note        string                  `json:"note"`
+	CreatedAt   time.Time               `json:"created_at"`
+
+	// Relationships
+	Product Product `json:"-"`
+	Actor   *User   `json:"-" gorm:"foreignKey:ActorID"`
+}
+
+// InventoryMovementReason explains why a product's stock changed.
+type InventoryMovementReason string
+
+// Inventory movement reason constants.
+const (
+	InventoryReasonOrder            InventoryMovementReason = "order"
+	InventoryReasonCancellation     InventoryMovementReason = "cancellation"
+	InventoryReasonManualAdjustment InventoryMovementReason = "manual_adjustment"
+	InventoryReasonReturn           InventoryMovementReason = "return"
+	InventoryReasonImport           InventoryMovementReason = "import"
+)
@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// EmailNotification records an email sent for a domain event, so a redelivered
+// event does not email the customer twice.
+type EmailNotification struct {
+	ID        uint64    `json:"id" gorm:"primaryKey"`
+	EventID   string    `json:"event_id" gorm:"type:uuid;not null"`
+	Template  string    `json:"template" gorm:"not null"`
+	Recipient string    `json:"recipient" gorm:"not null"`
+	UserID    *uint     `json:"user_id" gorm:"index"`
+	SentAt    time.Time `json:"sent_at" gorm:"autoCreateTime"`
+}
@@ -17,8 +17,10 @@ type Order struct {
 	DeletedAt   gorm.DeletedAt `json:"-" gorm:"index"`
 
 	// Relationships
-	User       User        `json:"user"`
-	OrderItems []OrderItem `json:"order_items"`
+	User          User                 `json:"user"`
+	OrderItems    []OrderItem          `json:"order_items"`
+	StatusHistory []OrderStatusHistory `json:"status_history"`
+	Payment       *Payment             `json:"payment"`
 }
 
 // OrderStatus represents the current status of an order.
@@ -26,13 +28,36 @@ type OrderStatus string
 
 // Order status constants.
 const (
-	OrderStatusPending   OrderStatus = "pending"
-	OrderStatusConfirmed OrderStatus = "confirmed"
-	OrderStatusShipped   OrderStatus = "shipped"
-	OrderStatusDelivered OrderStatus = "delivered"
-	OrderStatusCancelled OrderStatus = "cancelled"
+	// OrderStatusPendingPayment is the status of an order while its payment is being authorized
+	OrderStatusPendingPayment OrderStatus = "pending_payment"
+	OrderStatusPending        OrderStatus = "pending"
+	OrderStatusConfirmed      OrderStatus = "confirmed"
+	OrderStatusShipped        OrderStatus = "shipped"
+	OrderStatusDelivered      OrderStatus = "delivered"
+	OrderStatusCancelled      OrderStatus = "cancelled"
 )
 
+// OrderStatusHistory records a single status transition of an order.
+// FromStatus is nil for the entry written when the order is placed.
+type OrderStatusHistory struct {
+	ID         uint         `json:"id" gorm:"primaryKey"`
+	OrderID    uint         `json:"order_id" gorm:"not null;index"`
+	FromStatus *OrderStatus `json:"from_status"`
+	ToStatus   OrderStatus  `json:"to_status" gorm:"not null"`
+	ActorID    *uint        `json:"actor_id" gorm:"index"`
+	Reason     string       `json:"reason"`
+	CreatedAt  time.Time    `json:"created_at"`
+
+	// Relationships
+	Order Order `json:"-"`
+	Actor *User `json:"-" gorm:"foreignKey:ActorID"`
+}
+
+// TableName overrides the pluralized table name used by GORM.
+func (OrderStatusHistory) TableName() string {
+	return "order_status_history"
+}
+
 // OrderItem represents a single item within an order.
 type OrderItem struct {
 	ID        uint           `json:"id" gorm:"primaryKey"`
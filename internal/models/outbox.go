@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// OutboxEvent is a domain event written in the same transaction as the change
+// that caused it. The outbox relay publishes it to the event queue afterwards,
+// so events are delivered at least once and never for rolled back changes.
+// Published events are purged after a retention period; events that still
+// fail after the maximum number of attempts are marked dead and kept for an
+// operator to inspect.
+type OutboxEvent struct {
+	ID            uint64     `json:"id" gorm:"primaryKey"`
+	EventID       string     `json:"event_id" gorm:"type:uuid;uniqueIndex;not null"`
+	EventType     string     `json:"event_type" gorm:"not null;index"`
+	Envelope      string     `json:"envelope" gorm:"type:jsonb;not null"`
+	Attempts      int        `json:"attempts" gorm:"not null;default:0"`
+	LastError     string     `json:"last_error"`
+	NextAttemptAt time.Time  `json:"next_attempt_at" gorm:"not null"`
+	PublishedAt   *time.Time `json:"published_at"`
+	DeadAt        *time.Time `json:"dead_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
@@ -0,0 +1,71 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Payment represents a payment attempt for an order with an external payment provider.
+type Payment struct {
+	ID                uint           `json:"id" gorm:"primaryKey"`
+	OrderID           uint           `json:"order_id" gorm:"not null;index"`
+	Provider          string         `json:"provider" gorm:"not null"`
+	ProviderPaymentID string         `json:"provider_payment_id" gorm:"index"`
+	Amount            float64        `json:"amount" gorm:"not null"`
+	Currency          string         `json:"currency" gorm:"not null"`
+	Status            PaymentStatus  `json:"status" gorm:"default:pending"`
+	FailureReason     string         `json:"failure_reason"`
+	CreatedAt         time.Time      `json:"created_at"`
+	UpdatedAt         time.Time      `json:"updated_at"`
+	DeletedAt         gorm.DeletedAt `json:"-" gorm:"index"`
+
+	// Relationships
+	Order Order `json:"-"`
+}
+
+// PaymentStatus represents the current status of a payment.
+type PaymentStatus string
+
+// Payment status constants.
+const (
+	PaymentStatusPending    PaymentStatus = "pending"
+	PaymentStatusAuthorized PaymentStatus = "authorized"
+	PaymentStatusCaptured   PaymentStatus = "captured"
+	PaymentStatusDeclined   PaymentStatus = "declined"
+	PaymentStatusVoided     PaymentStatus = "voided"
+	PaymentStatusRefunded   PaymentStatus = "refunded"
+	PaymentStatusFailed     PaymentStatus = "failed"
+
+	// Captures, voids and refunds stay pending until the provider has carried
+	// them out; failed ones are retried
+	PaymentStatusCapturePending PaymentStatus = "capture_pending"
+	PaymentStatusVoidPending    PaymentStatus = "void_pending"
+	PaymentStatusRefundPending  PaymentStatus = "refund_pending"
+)
+
+// WebhookEvent stores a raw webhook received from a payment provider.
+// Provider and EventID are unique together so replayed deliveries are ignored.
+type WebhookEvent struct {
+	ID          uint               `json:"id" gorm:"primaryKey"`
+	Provider    string             `json:"provider" gorm:"not null;uniqueIndex:idx_webhook_events_provider_event"`
+	EventID     string             `json:"event_id" gorm:"not null;uniqueIndex:idx_webhook_events_provider_event"`
+	EventType   string             `json:"event_type"`
+	Payload     string             `json:"payload" gorm:"type:jsonb;not null"`
+	Status      WebhookEventStatus `json:"status" gorm:"default:received"`
+	Error       string             `json:"error"`
+	ProcessedAt *time.Time         `json:"processed_at"`
+	CreatedAt   time.Time          `json:"created_at"`
+	UpdatedAt   time.Time          `json:"updated_at"`
+}
+
+// WebhookEventStatus represents the processing state of a received webhook.
+type WebhookEventStatus string
+
+// Webhook event status constants.
+const (
+	WebhookEventStatusReceived  WebhookEventStatus = "received"
+	WebhookEventStatusProcessed WebhookEventStatus = "processed"
+	WebhookEventStatusFailed    WebhookEventStatus = "failed"
+	WebhookEventStatusIgnored   WebhookEventStatus = "ignored"
+)
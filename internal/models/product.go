@@ -20,19 +20,23 @@ type Category struct {
 	Products []Product `json:"-"`
 }
 
+// DefaultLowStockThreshold is used when a product is created without a threshold.
+const DefaultLowStockThreshold = 5
+
 // Product represents an item available for purchase.
 type Product struct {
-	ID          uint           `json:"id" gorm:"primaryKey"`
-	CategoryID  uint           `json:"category_id" gorm:"not null"`
-	Name        string         `json:"name" gorm:"not null"`
-	Description string         `json:"description"`
-	Price       float64        `json:"price" gorm:"not null"`
-	Stock       int            `json:"stock" gorm:"default:0"`
-	SKU         string         `json:"sku" gorm:"uniqueIndex;not null"`
-	IsActive    bool           `json:"is_active" gorm:"default:true"`
-	CreatedAt   time.Time      `json:"created_at"`
-	UpdatedAt   time.Time      `json:"updated_at"`
-	DeletedAt   gorm.DeletedAt `json:"-" gorm:"index"`
+	ID                uint           `json:"id" gorm:"primaryKey"`
+	CategoryID        uint           `json:"category_id" gorm:"not null"`
+	Name              string         `json:"name" gorm:"not null"`
+	Description       string         `json:"description"`
+	Price             float64        `json:"price" gorm:"not null"`
+	Stock             int            `json:"stock" gorm:"default:0"`
+	LowStockThreshold int            `json:"low_stock_threshold" gorm:"not null"`
+	SKU               string         `json:"sku" gorm:"uniqueIndex;not null"`
+	IsActive          bool           `json:"is_active" gorm:"default:true"`
+	CreatedAt         time.Time      `json:"created_at"`
+	UpdatedAt         time.Time      `json:"updated_at"`
+	DeletedAt         gorm.DeletedAt `json:"-" gorm:"index"`
 
 	// Relationships
 	Category   Category       `json:"category"`
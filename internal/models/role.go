@@ -0,0 +1,53 @@
+package models
+
+import "time"
+
+// Role is a named set of permissions granted to staff accounts. A user can
+// hold several roles; their permissions add up.
+type Role struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	Name        string    `json:"name" gorm:"uniqueIndex;not null"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+
+	// Relationships
+	Permissions []Permission `json:"permissions" gorm:"many2many:role_permissions"`
+}
+
+// Permission is a single action on a resource, named "resource:action".
+type Permission struct {
+	ID          uint   `json:"id" gorm:"primaryKey"`
+	Name        string `json:"name" gorm:"uniqueIndex;not null"`
+	Description string `json:"description"`
+}
+
+// RoleAssignment grants a role to a user.
+type RoleAssignment struct {
+	UserID     uint      `json:"user_id" gorm:"primaryKey"`
+	RoleID     uint      `json:"role_id" gorm:"primaryKey"`
+	AssignedBy *uint     `json:"assigned_by"`
+	CreatedAt  time.Time `json:"created_at"`
+
+	// Relationships
+	Role Role `json:"-"`
+}
+
+// TableName overrides the table name used by GORM.
+func (RoleAssignment) TableName() string {
+	return "user_roles"
+}
+
+// Permission name constants, seeded by the migrations.
+const (
+	PermissionCategoriesWrite = "categories:write"
+	PermissionProductsWrite   = "products:write"
+	PermissionInventoryRead   = "inventory:read"
+	PermissionInventoryWrite  = "inventory:write"
+	PermissionOrdersRead      = "orders:read"
+	PermissionOrdersUpdate    = "orders:update"
+	PermissionUsersUpdate     = "users:update"
+	PermissionUsersUnlock     = "users:unlock"
+	PermissionWebhooksManage  = "webhooks:manage"
+	PermissionRolesManage     = "roles:manage"
+)
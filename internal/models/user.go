@@ -8,17 +8,27 @@ import (
 
 // User represents a user account in the system.
 type User struct {
-	ID        uint           `json:"id" gorm:"primaryKey"`
-	Email     string         `json:"email" gorm:"uniqueIndex;not null"`
-	Password  string         `json:"-" gorm:"not null"`
-	FirstName string         `json:"first_name" gorm:"not null"`
-	LastName  string         `json:"last_name" gorm:"not null"`
-	Phone     string         `json:"phone" gorm:"not null"`
-	IsActive  bool           `json:"is_active" gorm:"default:true"`
-	Role      UserRole       `json:"role" gorm:"default:customer"`
-	CreatedAt time.Time      `json:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at"`
-	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
+	ID              uint       `json:"id" gorm:"primaryKey"`
+	Email           string     `json:"email" gorm:"uniqueIndex;not null"`
+	Password        string     `json:"-" gorm:"not null"`
+	FirstName       string     `json:"first_name" gorm:"not null"`
+	LastName        string     `json:"last_name" gorm:"not null"`
+	Phone           string     `json:"phone" gorm:"not null"`
+	IsActive        bool       `json:"is_active" gorm:"default:true"`
+	EmailVerifiedAt *time.Time `json:"email_verified_at"`
+	// FailedLoginAttempts counts consecutive wrong passwords; from the second one on
+	// LoginNotBefore holds off the next attempt, and reaching the limit sets LockedUntil
+	FailedLoginAttempts int        `json:"-" gorm:"not null;default:0"`
+	LoginNotBefore      *time.Time `json:"-"`
+	LockedUntil         *time.Time `json:"locked_until"`
+	// TOTPSecret is encrypted. It is set during enrollment and only used for
+	// login once TOTPEnabledAt is set. TOTPLastUsedStep stops a code being used twice
+	TOTPSecret       *string        `json:"-"`
+	TOTPEnabledAt    *time.Time     `json:"totp_enabled_at"`
+	TOTPLastUsedStep *int64         `json:"-"`
+	CreatedAt        time.Time      `json:"created_at"`
+	UpdatedAt        time.Time      `json:"updated_at"`
+	DeletedAt        gorm.DeletedAt `json:"-" gorm:"index"`
 
 	// Relationships
 	RefreshTokens []RefreshToken `json:"-"`
@@ -26,24 +36,116 @@ type User struct {
 	Cart          Cart           `json:"-"`
 }
 
-// UserRole represents the role of a user.
-type UserRole string
-
-// User role constants.
-const (
-	UserRoleAdmin    UserRole = "admin"
-	UserRoleCustomer UserRole = "customer"
-)
-
-// RefreshToken represents a JWT refresh token for a user.
+// RefreshToken represents a JWT refresh token for a user, stored as a SHA-256
+// hash. Refreshing rotates the token: the used one is marked rotated and a new
+// one joins the same family. A family is one signed-in session, described by
+// the device and address it was last refreshed from.
 type RefreshToken struct {
 	ID        uint           `json:"id" gorm:"primaryKey"`
 	UserID    uint           `json:"user_id" gorm:"not null"`
-	Token     string         `json:"token" gorm:"uniqueIndex;not null"`
+	TokenHash string         `json:"-" gorm:"uniqueIndex;not null"`
+	FamilyID  string         `json:"family_id" gorm:"type:uuid;index;not null"`
+	UserAgent string         `json:"user_agent"`
+	IPAddress string         `json:"ip_address"`
 	ExpiresAt time.Time      `json:"expires_at" gorm:"not null"`
+	RotatedAt *time.Time     `json:"rotated_at"`
 	CreatedAt time.Time      `json:"created_at"`
 	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
 
+	// The access token issued together with this refresh token
+	AccessTokenID        string    `json:"-" gorm:"type:uuid"`
+	AccessTokenExpiresAt time.Time `json:"-"`
+
+	// Relationships
+	User User `json:"-"`
+}
+
+// UserToken is a single-use token emailed to a user. Only an HMAC of the
+// token is kept once the email is sent, so a leaked table cannot be used to
+// verify or take over accounts.
+type UserToken struct {
+	ID        uint             `json:"id" gorm:"primaryKey"`
+	UserID    uint             `json:"user_id" gorm:"not null"`
+	Purpose   UserTokenPurpose `json:"purpose" gorm:"not null"`
+	TokenHash string           `json:"-" gorm:"uniqueIndex;not null"`
+	// TokenCiphertext holds the encrypted token until its email has been sent
+	TokenCiphertext *string    `json:"-"`
+	ExpiresAt       time.Time  `json:"expires_at" gorm:"not null"`
+	UsedAt          *time.Time `json:"used_at"`
+	CreatedAt       time.Time  `json:"created_at"`
+
+	// Relationships
+	User User `json:"-"`
+}
+
+// UserTokenPurpose is what a UserToken can be redeemed for.
+type UserTokenPurpose string
+
+// User token purpose constants.
+const (
+	UserTokenPurposeEmailVerification UserTokenPurpose = "email_verification"
+)
+
+// PasswordResetToken is a single-use token that lets a user choose a new
+// password. Like UserToken, only an HMAC of the token is kept once the email is sent.
+type PasswordResetToken struct {
+	ID          uint       `json:"id" gorm:"primaryKey"`
+	UserID      uint       `json:"user_id" gorm:"not null"`
+	TokenHash   string     `json:"-" gorm:"uniqueIndex;not null"`
+	RequestedIP string     `json:"requested_ip"`
+	ExpiresAt   time.Time  `json:"expires_at" gorm:"not null"`
+	UsedAt      *time.Time `json:"used_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+
+	// TokenCiphertext holds the encrypted token until its email has been sent
+	TokenCiphertext *string `json:"-"`
+
+	// Relationships
+	User User `json:"-"`
+}
+
+// RevokedAccessToken is an access token that must be rejected before it
+// expires. Rows can be purged once ExpiresAt has passed.
+type RevokedAccessToken struct {
+	TokenID   string    `json:"token_id" gorm:"type:uuid;primaryKey"`
+	UserID    uint      `json:"user_id" gorm:"not null"`
+	ExpiresAt time.Time `json:"expires_at" gorm:"not null;index"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// LoginAttempt records a password login, successful or not, to throttle
+// password guessing per IP address and to audit sign-ins.
+type LoginAttempt struct {
+	ID        uint64    `json:"id" gorm:"primaryKey"`
+	Email     string    `json:"email" gorm:"not null"`
+	UserID    *uint     `json:"user_id"`
+	IPAddress string    `json:"ip_address" gorm:"not null"`
+	Succeeded bool      `json:"succeeded" gorm:"not null"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// RecoveryCode is a single-use code that replaces a TOTP code when the
+// authenticator is lost. Only an HMAC of the code is stored.
+type RecoveryCode struct {
+	ID        uint       `json:"id" gorm:"primaryKey"`
+	UserID    uint       `json:"user_id" gorm:"not null"`
+	CodeHash  string     `json:"-" gorm:"not null"`
+	UsedAt    *time.Time `json:"used_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// MFAChallenge is the second step of a login for an account with two-factor
+// authentication. It is handed out once the password is checked and redeemed
+// with a TOTP or recovery code. Only an HMAC of the token is stored.
+type MFAChallenge struct {
+	ID        uint       `json:"id" gorm:"primaryKey"`
+	UserID    uint       `json:"user_id" gorm:"not null"`
+	TokenHash string     `json:"-" gorm:"uniqueIndex;not null"`
+	Attempts  int        `json:"attempts" gorm:"not null;default:0"`
+	ExpiresAt time.Time  `json:"expires_at" gorm:"not null"`
+	UsedAt    *time.Time `json:"used_at"`
+	CreatedAt time.Time  `json:"created_at"`
+
 	// Relationships
 	User User `json:"-"`
 }
@@ -0,0 +1,114 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// WebhookSubscription is a partner endpoint that receives domain events.
+// Every delivery is signed with Secret so the receiver can verify it came from us.
+type WebhookSubscription struct {
+	ID          uint           `json:"id" gorm:"primaryKey"`
+	URL         string         `json:"url" gorm:"not null"`
+	Secret      string         `json:"-" gorm:"not null"`
+	EventTypes  StringList     `json:"event_types" gorm:"type:jsonb;not null"`
+	Description string         `json:"description"`
+	IsActive    bool           `json:"is_active" gorm:"default:true"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `json:"-" gorm:"index"`
+
+	// Relationships
+	Deliveries []WebhookDelivery `json:"-" gorm:"foreignKey:SubscriptionID"`
+}
+
+// WebhookEventTypeAll subscribes to every event type.
+const WebhookEventTypeAll = "*"
+
+// Subscribes reports whether the subscription wants events of eventType.
+func (s *WebhookSubscription) Subscribes(eventType string) bool {
+	for _, subscribed := range s.EventTypes {
+		if subscribed == eventType || subscribed == WebhookEventTypeAll {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookDelivery is one event to be sent to one subscription.
+// SubscriptionID and EventID are unique together so an event is never fanned out twice.
+type WebhookDelivery struct {
+	ID             uint64                `json:"id" gorm:"primaryKey"`
+	SubscriptionID uint                  `json:"subscription_id" gorm:"not null;uniqueIndex:uq_webhook_deliveries_subscription_event"`
+	EventID        string                `json:"event_id" gorm:"type:uuid;not null;uniqueIndex:uq_webhook_deliveries_subscription_event"`
+	EventType      string                `json:"event_type" gorm:"not null"`
+	Payload        string                `json:"payload" gorm:"type:jsonb;not null"`
+	Status         WebhookDeliveryStatus `json:"status" gorm:"default:pending"`
+	AttemptCount   int                   `json:"attempt_count" gorm:"not null;default:0"`
+	LastStatusCode *int                  `json:"last_status_code"`
+	LastError      string                `json:"last_error"`
+	NextAttemptAt  time.Time             `json:"next_attempt_at" gorm:"not null"`
+	DeliveredAt    *time.Time            `json:"delivered_at"`
+	CreatedAt      time.Time             `json:"created_at"`
+	UpdatedAt      time.Time             `json:"updated_at"`
+
+	// Relationships
+	Subscription WebhookSubscription      `json:"-"`
+	Attempts     []WebhookDeliveryAttempt `json:"attempts,omitempty" gorm:"foreignKey:DeliveryID"`
+}
+
+// WebhookDeliveryStatus represents the state of a webhook delivery.
+type WebhookDeliveryStatus string
+
+// Webhook delivery status constants.
+const (
+	WebhookDeliveryStatusPending   WebhookDeliveryStatus = "pending"
+	WebhookDeliveryStatusSucceeded WebhookDeliveryStatus = "succeeded"
+	WebhookDeliveryStatusFailed    WebhookDeliveryStatus = "failed"
+)
+
+// WebhookDeliveryAttempt logs a single HTTP request made for a delivery.
+type WebhookDeliveryAttempt struct {
+	ID           uint64    `json:"id" gorm:"primaryKey"`
+	DeliveryID   uint64    `json:"delivery_id" gorm:"not null;index"`
+	StatusCode   *int      `json:"status_code"`
+	Error        string    `json:"error"`
+	ResponseBody string    `json:"response_body"`
+	DurationMs   int       `json:"duration_ms" gorm:"not null;default:0"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// StringList is a list of strings stored as a JSON array.
+type StringList []string
+
+// Value implements driver.Valuer.
+func (l StringList) Value() (driver.Value, error) {
+	if l == nil {
+		return "[]", nil
+	}
+
+	data, err := json.Marshal([]string(l))
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan implements sql.Scanner.
+func (l *StringList) Scan(value any) error {
+	switch v := value.(type) {
+	case nil:
+		*l = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(v, l)
+	case string:
+		return json.Unmarshal([]byte(v), l)
+	default:
+		return errors.New("unsupported type for StringList")
+	}
+}
@@ -0,0 +1,35 @@
+package notifications
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// FileMailer writes every message as an .eml file instead of sending it, for
+// local development. The files open in any mail client.
+type FileMailer struct {
+	basePath string
+	from     string
+}
+
+func NewFileMailer(basePath, from string) *FileMailer {
+	return &FileMailer{basePath: basePath, from: from}
+}
+
+func (m *FileMailer) Send(msg *Message) error {
+	data, err := buildMIME(m.from, msg)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(m.basePath, 0o755); err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("%s-%s.eml", time.Now().UTC().Format("20060102T150405"), uuid.NewString())
+	return os.WriteFile(filepath.Join(m.basePath, name), data, 0o644)
+}
@@ -0,0 +1,116 @@
+package notifications
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"strings"
+	texttemplate "text/template"
+	"time"
+)
+
+// Template names.
+const (
+	TemplateWelcome           = "welcome"
+	TemplateVerifyEmail       = "verify_email"
+	TemplatePasswordReset     = "password_reset"
+	TemplatePasswordChanged   = "password_changed"
+	TemplateOrderConfirmation = "order_confirmation"
+	TemplateOrderShipped      = "order_shipped"
+	TemplateOrderCancelled    = "order_cancelled"
+)
+
+//go:embed templates
+var templateFS embed.FS
+
+// Templates renders the emails in templates/. Every template has a <name>.txt
+// file, which also defines "<name>.subject", and a <name>.html file.
+type Templates struct {
+	text *texttemplate.Template
+	html *htmltemplate.Template
+}
+
+// AccountData is rendered by the welcome and password_changed templates.
+type AccountData struct {
+	FirstName string
+	Email     string
+}
+
+// VerifyEmailData is rendered by the verify_email template.
+type VerifyEmailData struct {
+	FirstName string
+	VerifyURL string
+	ExpiresAt time.Time
+}
+
+// PasswordResetData is rendered by the password_reset template.
+type PasswordResetData struct {
+	FirstName string
+	ResetURL  string
+	ExpiresAt time.Time
+}
+
+// OrderData is rendered by the order templates.
+type OrderData struct {
+	FirstName   string
+	OrderID     uint
+	Items       []OrderLine
+	TotalAmount float64
+	Reason      string
+}
+
+// OrderLine is a single product of an order.
+type OrderLine struct {
+	Name     string
+	Quantity int
+	Price    float64
+}
+
+// Subtotal is the price of the line times its quantity.
+func (l OrderLine) Subtotal() float64 {
+	return l.Price * float64(l.Quantity)
+}
+
+var funcs = map[string]any{
+	"money": func(amount float64) string { return fmt.Sprintf("$%.2f", amount) },
+	"date":  func(t time.Time) string { return t.UTC().Format("January 2, 2006 15:04 MST") },
+}
+
+func LoadTemplates() (*Templates, error) {
+	text, err := texttemplate.New("").Funcs(funcs).ParseFS(templateFS, "templates/*.txt")
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse text templates: %w", err)
+	}
+
+	html, err := htmltemplate.New("").Funcs(funcs).ParseFS(templateFS, "templates/*.html")
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse html templates: %w", err)
+	}
+
+	return &Templates{text: text, html: html}, nil
+}
+
+// Render builds the message for a template addressed to the given recipient.
+func (t *Templates) Render(name, to string, data any) (*Message, error) {
+	var subject, text, html bytes.Buffer
+
+	if err := t.text.ExecuteTemplate(&subject, name+".subject", data); err != nil {
+		return nil, err
+	}
+
+	if err := t.text.ExecuteTemplate(&text, name+".txt", data); err != nil {
+		return nil, err
+	}
+
+	if err := t.html.ExecuteTemplate(&html, name+".html", data); err != nil {
+		return nil, err
+	}
+
+	return &Message{
+		To:       to,
+		Subject:  strings.TrimSpace(subject.String()),
+		TextBody: text.String(),
+		HTMLBody: html.String(),
+	}, nil
+}
@@ -0,0 +1,110 @@
+package providers
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/tomimandalaputra/e-commerce-go/internal/interfaces"
+)
+
+// Card tokens understood by the fake payment provider. Any other non-empty
+// token is approved.
+const (
+	FakeTokenDecline           = "tok_decline"
+	FakeTokenInsufficientFunds = "tok_insufficient_funds"
+	FakeTokenExpiredCard       = "tok_expired_card"
+	FakeTokenProviderError     = "tok_provider_error"
+)
+
+const fakePaymentIDPrefix = "fake_pay_"
+
+// FakePaymentProvider is an in-process payment provider for local development.
+// It approves or declines deterministically based on the card token so the
+// whole checkout flow can be exercised without an external service.
+type FakePaymentProvider struct{}
+
+func NewFakePaymentProvider() *FakePaymentProvider {
+	return &FakePaymentProvider{}
+}
+
+func (p *FakePaymentProvider) Name() string {
+	return "fake"
+}
+
+func (p *FakePaymentProvider) Authorize(req *interfaces.PaymentRequest) (*interfaces.PaymentResult, error) {
+	if req.Token == "" {
+		return nil, errors.New("payment token is required")
+	}
+
+	result := &interfaces.PaymentResult{
+		ProviderPaymentID: fakePaymentIDPrefix + uuid.New().String(),
+	}
+
+	switch req.Token {
+	case FakeTokenDecline:
+		result.DeclineReason = "card_declined"
+	case FakeTokenInsufficientFunds:
+		result.DeclineReason = "insufficient_funds"
+	case FakeTokenExpiredCard:
+		result.DeclineReason = "expired_card"
+	case FakeTokenProviderError:
+		return nil, errors.New("fake payment provider unavailable")
+	default:
+		result.Approved = true
+	}
+
+	return result, nil
+}
+
+func (p *FakePaymentProvider) Capture(providerPaymentID string, _ float64) (*interfaces.PaymentResult, error) {
+	return p.settle(providerPaymentID)
+}
+
+func (p *FakePaymentProvider) Refund(providerPaymentID string, _ float64) (*interfaces.PaymentResult, error) {
+	return p.settle(providerPaymentID)
+}
+
+func (p *FakePaymentProvider) Void(providerPaymentID string) (*interfaces.PaymentResult, error) {
+	return p.settle(providerPaymentID)
+}
+
+func (p *FakePaymentProvider) settle(providerPaymentID string) (*interfaces.PaymentResult, error) {
+	if !strings.HasPrefix(providerPaymentID, fakePaymentIDPrefix) {
+		return nil, errors.New("unknown payment")
+	}
+
+	return &interfaces.PaymentResult{
+		ProviderPaymentID: providerPaymentID,
+		Approved:          true,
+	}, nil
+}
+
+type fakeWebhookPayload struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+	Data struct {
+		PaymentID     string `json:"payment_id"`
+		FailureReason string `json:"failure_reason"`
+	} `json:"data"`
+}
+
+func (p *FakePaymentProvider) ParseWebhook(payload []byte) (*interfaces.PaymentWebhookEvent, error) {
+	var body fakeWebhookPayload
+	if err := json.Unmarshal(payload, &body); err != nil {
+		return nil, err
+	}
+
+	if body.ID == "" {
+		return nil, errors.New("webhook event id is required")
+	}
+
+	return &interfaces.PaymentWebhookEvent{
+		EventID:           body.ID,
+		Type:              body.Type,
+		ProviderPaymentID: body.Data.PaymentID,
+		FailureReason:     body.Data.FailureReason,
+	}, nil
+}
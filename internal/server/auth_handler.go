@@ -1,8 +1,14 @@
 package server
 
 import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+
 	"github.com/gin-gonic/gin"
 	"github.com/tomimandalaputra/e-commerce-go/internal/dto"
+	"github.com/tomimandalaputra/e-commerce-go/internal/services"
 	"github.com/tomimandalaputra/e-commerce-go/internal/utils"
 )
 
@@ -22,7 +28,7 @@ func (s *Server) register(c *gin.Context) {
 		return
 	}
 
-	response, err := s.authService.Register(&req)
+	response, err := s.authService.Register(&req, clientInfo(c))
 	if err != nil {
 		utils.BadRequestResponse(c, "Registration failed", err)
 		return
@@ -32,13 +38,14 @@ func (s *Server) register(c *gin.Context) {
 }
 
 // @Summary User login
-// @Description Authenticate user with email and password
+// @Description Authenticate user with email and password. Accounts with two-factor authentication get an mfa_token to complete the login at /auth/mfa/verify
 // @Tags Authentication
 // @Accept json
 // @Produce json
 // @Param request body dto.LoginRequest true "User login credentials"
-// @Success 200 {object} utils.Response{data=dto.AuthResponse} "Login successful"
+// @Success 200 {object} utils.Response{data=dto.LoginResponse} "Login successful, or an MFA challenge when two-factor authentication is enabled"
 // @Failure 401 {object} utils.Response "Invalid credentials"
+// @Failure 429 {object} utils.Response "Too many failed login attempts, see the Retry-After header"
 // @Router /auth/login [post]
 func (s *Server) login(c *gin.Context) {
 	var req dto.LoginRequest
@@ -47,12 +54,62 @@ func (s *Server) login(c *gin.Context) {
 		return
 	}
 
-	response, err := s.authService.Login(&req)
+	response, err := s.authService.Login(&req, clientInfo(c))
 	if err != nil {
+		var throttled *services.LoginThrottledError
+		if errors.As(err, &throttled) {
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(throttled.RetryAfter.Seconds()))))
+			utils.ErrorResponse(c, http.StatusTooManyRequests, "Too many failed login attempts, try again later", nil)
+			return
+		}
+
 		utils.UnauthorizedResponse(c, "Login failed")
 		return
 	}
 
+	if response.MFARequired {
+		utils.SuccessResponse(c, "Two-factor authentication required", response)
+		return
+	}
+
+	utils.SuccessResponse(c, "Login successful", response)
+}
+
+// @Summary Verify two-factor authentication
+// @Description Complete a login with the mfa_token from /auth/login and a TOTP code or a recovery code
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param request body dto.VerifyMFARequest true "MFA challenge token and code"
+// @Success 200 {object} utils.Response{data=dto.AuthResponse} "Login successful"
+// @Failure 400 {object} utils.Response "Invalid request data"
+// @Failure 401 {object} utils.Response "Invalid or expired challenge, or invalid code"
+// @Failure 429 {object} utils.Response "Too many failed login attempts, see the Retry-After header"
+// @Router /auth/mfa/verify [post]
+func (s *Server) verifyMFA(c *gin.Context) {
+	var req dto.VerifyMFARequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request data", err)
+		return
+	}
+
+	response, err := s.authService.VerifyMFA(&req, clientInfo(c))
+	if err != nil {
+		var throttled *services.LoginThrottledError
+		switch {
+		case errors.As(err, &throttled):
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(throttled.RetryAfter.Seconds()))))
+			utils.ErrorResponse(c, http.StatusTooManyRequests, "Too many failed login attempts, try again later", nil)
+		case errors.Is(err, services.ErrInvalidMFAChallenge):
+			utils.UnauthorizedResponse(c, "Invalid or expired MFA challenge")
+		case errors.Is(err, services.ErrInvalidMFACode):
+			utils.UnauthorizedResponse(c, "Invalid two-factor authentication code")
+		default:
+			utils.InternalServerErrorResponse(c, "Failed to verify two-factor authentication", err)
+		}
+		return
+	}
+
 	utils.SuccessResponse(c, "Login successful", response)
 }
 
@@ -72,7 +129,7 @@ func (s *Server) refreshToken(c *gin.Context) {
 		return
 	}
 
-	response, err := s.authService.RefreshToken(&req)
+	response, err := s.authService.RefreshToken(&req, clientInfo(c))
 	if err != nil {
 		utils.UnauthorizedResponse(c, "Token refresh failed")
 		return
@@ -105,6 +162,110 @@ func (s *Server) logout(c *gin.Context) {
 	utils.SuccessResponse(c, "Logout successful", nil)
 }
 
+// @Summary Verify email address
+// @Description Confirm the account's email address with the token from the verification email. Each token can be used once
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param request body dto.VerifyEmailRequest true "Verification token"
+// @Success 200 {object} utils.Response "Email verified successfully"
+// @Failure 400 {object} utils.Response "Invalid or expired verification token"
+// @Router /auth/verify-email [post]
+func (s *Server) verifyEmail(c *gin.Context) {
+	var req dto.VerifyEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request data", err)
+		return
+	}
+
+	if err := s.authService.VerifyEmail(&req); err != nil {
+		if errors.Is(err, services.ErrInvalidVerificationToken) {
+			utils.BadRequestResponse(c, "Invalid or expired verification token", err)
+			return
+		}
+		utils.InternalServerErrorResponse(c, "Email verification failed", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "Email verified successfully", nil)
+}
+
+// @Summary Resend verification email
+// @Description Send a new verification email to an unverified account. The response is the same whether or not the account exists
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param request body dto.ResendVerificationRequest true "Account email"
+// @Success 200 {object} utils.Response "Verification email sent if the account needs one"
+// @Failure 400 {object} utils.Response "Invalid request data"
+// @Router /auth/resend-verification [post]
+func (s *Server) resendVerification(c *gin.Context) {
+	var req dto.ResendVerificationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request data", err)
+		return
+	}
+
+	if err := s.authService.ResendVerification(&req); err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to resend verification email", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "Verification email sent if the account needs one", nil)
+}
+
+// @Summary Request a password reset
+// @Description Email a single-use password reset link. The response is the same whether or not the account exists
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param request body dto.ForgotPasswordRequest true "Account email"
+// @Success 200 {object} utils.Response "Password reset email sent if the account exists"
+// @Failure 400 {object} utils.Response "Invalid request data"
+// @Router /auth/forgot-password [post]
+func (s *Server) forgotPassword(c *gin.Context) {
+	var req dto.ForgotPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request data", err)
+		return
+	}
+
+	if err := s.authService.ForgotPassword(&req, c.ClientIP()); err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to request password reset", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "Password reset email sent if the account exists", nil)
+}
+
+// @Summary Reset password
+// @Description Set a new password with the token from the password reset email. All sessions of the account are signed out
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param request body dto.ResetPasswordRequest true "Reset token and new password"
+// @Success 200 {object} utils.Response "Password reset successfully"
+// @Failure 400 {object} utils.Response "Invalid request data or invalid or expired reset token"
+// @Router /auth/reset-password [post]
+func (s *Server) resetPassword(c *gin.Context) {
+	var req dto.ResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request data", err)
+		return
+	}
+
+	if err := s.authService.ResetPassword(&req); err != nil {
+		if errors.Is(err, services.ErrInvalidResetToken) {
+			utils.BadRequestResponse(c, "Invalid or expired password reset token", err)
+			return
+		}
+		utils.InternalServerErrorResponse(c, "Password reset failed", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "Password reset successfully", nil)
+}
+
 // @Summary Get user profile
 // @Description Get current authenticated user's profile information
 // @Tags User
@@ -153,3 +314,167 @@ func (s *Server) updateProfile(c *gin.Context) {
 
 	utils.SuccessResponse(c, "Prolie update successfully", profile)
 }
+
+// @Summary Change password
+// @Description Change the current user's password. Requires the current password and signs out every session
+// @Tags User
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.ChangePasswordRequest true "Current and new password"
+// @Success 200 {object} utils.Response "Password changed successfully"
+// @Failure 400 {object} utils.Response "Invalid request data or incorrect current password"
+// @Failure 401 {object} utils.Response "Unauthorized"
+// @Router /users/password [put]
+func (s *Server) changePassword(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	var req dto.ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request data", err)
+		return
+	}
+
+	if err := s.userService.ChangePassword(userID, &req); err != nil {
+		if errors.Is(err, services.ErrIncorrectPassword) {
+			utils.BadRequestResponse(c, "Current password is incorrect", err)
+			return
+		}
+		utils.InternalServerErrorResponse(c, "Failed to change password", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "Password changed successfully", nil)
+}
+
+// @Summary Get sessions
+// @Description List the current user's signed-in sessions with the device and IP address they were issued to
+// @Tags User
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.Response{data=[]dto.SessionResponse} "Sessions retrieved successfully"
+// @Failure 401 {object} utils.Response "Unauthorized"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /users/sessions [get]
+func (s *Server) getSessions(c *gin.Context) {
+	sessions, err := s.userService.GetSessions(c.GetUint("user_id"))
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch sessions", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "Sessions retrieved successfully", sessions)
+}
+
+// @Summary Revoke session
+// @Description Sign out one session of the current user by revoking its refresh token
+// @Tags User
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Session ID"
+// @Success 200 {object} utils.Response "Session revoked successfully"
+// @Failure 400 {object} utils.Response "Invalid session ID"
+// @Failure 401 {object} utils.Response "Unauthorized"
+// @Failure 404 {object} utils.Response "Session not found"
+// @Router /users/sessions/{id} [delete]
+func (s *Server) revokeSession(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid session ID", err)
+		return
+	}
+
+	if err := s.userService.RevokeSession(c.GetUint("user_id"), uint(id)); err != nil {
+		if errors.Is(err, services.ErrSessionNotFound) {
+			utils.NotFoundResponse(c, "Session not found")
+			return
+		}
+		utils.InternalServerErrorResponse(c, "Failed to revoke session", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "Session revoked successfully", nil)
+}
+
+// @Summary Update user status
+// @Description Activate or deactivate a user account. Deactivation signs the user out of every session immediately (requires users:update)
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Param request body dto.UpdateUserStatusRequest true "New account status"
+// @Success 200 {object} utils.Response{data=dto.UserResponse} "User status updated successfully"
+// @Failure 400 {object} utils.Response "Invalid request data or deactivating your own account"
+// @Failure 401 {object} utils.Response "Unauthorized"
+// @Failure 403 {object} utils.Response "Permission users:update required"
+// @Failure 404 {object} utils.Response "User not found"
+// @Router /admin/users/{id}/status [put]
+func (s *Server) updateUserStatus(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid user ID", err)
+		return
+	}
+
+	var req dto.UpdateUserStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request data", err)
+		return
+	}
+
+	user, err := s.userService.UpdateUserStatus(c.GetUint("user_id"), uint(id), &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrUserNotFound):
+			utils.NotFoundResponse(c, "User not found")
+		case errors.Is(err, services.ErrCannotDeactivateSelf):
+			utils.BadRequestResponse(c, "You cannot deactivate your own account", err)
+		default:
+			utils.InternalServerErrorResponse(c, "Failed to update user status", err)
+		}
+		return
+	}
+
+	utils.SuccessResponse(c, "User status updated successfully", user)
+}
+
+// @Summary Unlock user
+// @Description Lift a temporary login lockout and reset the failed login counter of a user (requires users:unlock)
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Success 200 {object} utils.Response{data=dto.UserResponse} "User unlocked successfully"
+// @Failure 400 {object} utils.Response "Invalid user ID"
+// @Failure 401 {object} utils.Response "Unauthorized"
+// @Failure 403 {object} utils.Response "Permission users:unlock required"
+// @Failure 404 {object} utils.Response "User not found"
+// @Router /admin/users/{id}/unlock [post]
+func (s *Server) unlockUser(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid user ID", err)
+		return
+	}
+
+	user, err := s.userService.UnlockUser(uint(id))
+	if err != nil {
+		if errors.Is(err, services.ErrUserNotFound) {
+			utils.NotFoundResponse(c, "User not found")
+			return
+		}
+
+		utils.InternalServerErrorResponse(c, "Failed to unlock user", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "User unlocked successfully", user)
+}
+
+func clientInfo(c *gin.Context) *services.ClientInfo {
+	return &services.ClientInfo{
+		UserAgent: c.Request.UserAgent(),
+		IPAddress: c.ClientIP(),
+	}
+}
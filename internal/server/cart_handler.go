@@ -36,9 +36,11 @@ func (s *Server) getCart(c *gin.Context) {
 // @Produce json
 // @Security BearerAuth
 // @Param request body dto.AddToCartRequest true "Item to add to cart"
+// @Param Idempotency-Key header string false "Unique key to safely retry this request"
 // @Success 200 {object} utils.Response{data=dto.CartResponse} "Item added to cart successfully"
 // @Failure 400 {object} utils.Response "Invalid request data or insufficient stock"
 // @Failure 401 {object} utils.Response "Unauthorized"
+// @Failure 409 {object} utils.Response "Idempotency-Key reused with a different request"
 // @Router /cart/items [post]
 func (s *Server) addToCart(c *gin.Context) {
 
@@ -120,3 +122,24 @@ func (s *Server) removeFromCart(c *gin.Context) {
 
 	utils.SuccessResponse(c, "Item removed from cart successfully", nil)
 }
+
+// @Summary Begin checkout
+// @Description Reserve stock for every item in the user's cart while they complete payment. Reservations expire after the configured TTL; calling this again restarts the timer.
+// @Tags Cart
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.Response{data=dto.CheckoutResponse} "Checkout started successfully"
+// @Failure 400 {object} utils.Response "Cart is empty or insufficient stock"
+// @Failure 401 {object} utils.Response "Unauthorized"
+// @Router /cart/checkout [post]
+func (s *Server) beginCheckout(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	checkout, err := s.inventoryService.ReserveCart(userID)
+	if err != nil {
+		utils.BadRequestResponse(c, "Failed to start checkout", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "Checkout started successfully", checkout)
+}
@@ -0,0 +1,113 @@
+package server
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tomimandalaputra/e-commerce-go/internal/dto"
+	"github.com/tomimandalaputra/e-commerce-go/internal/services"
+	"github.com/tomimandalaputra/e-commerce-go/internal/utils"
+)
+
+// @Summary Get product inventory movements
+// @Description Retrieve the paginated stock ledger of a product, newest first (requires inventory:read)
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Product ID"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} utils.PaginatedResponse{data=[]dto.InventoryMovementResponse} "Inventory movements retrieved successfully"
+// @Failure 400 {object} utils.Response "Invalid product ID"
+// @Failure 401 {object} utils.Response "Unauthorized"
+// @Failure 403 {object} utils.Response "Permission inventory:read required"
+// @Failure 404 {object} utils.Response "Product not found"
+// @Router /admin/products/{id}/inventory [get]
+func (s *Server) getProductInventory(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid product ID", err)
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	movements, meta, err := s.inventoryService.GetMovements(uint(id), page, limit)
+	if err != nil {
+		if errors.Is(err, services.ErrProductNotFound) {
+			utils.NotFoundResponse(c, "Product not found")
+			return
+		}
+		utils.InternalServerErrorResponse(c, "Failed to fetch inventory movements", err)
+		return
+	}
+
+	utils.PaginatedSuccessResponse(c, "Inventory movements retrieved successfully", movements, *meta)
+}
+
+// @Summary Adjust product inventory
+// @Description Add or remove stock for a product and record the reason in the inventory ledger (requires inventory:write)
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Product ID"
+// @Param request body dto.AdjustInventoryRequest true "Stock adjustment"
+// @Success 200 {object} utils.Response{data=dto.InventoryMovementResponse} "Inventory adjusted successfully"
+// @Failure 400 {object} utils.Response "Invalid request data or insufficient stock"
+// @Failure 401 {object} utils.Response "Unauthorized"
+// @Failure 403 {object} utils.Response "Permission inventory:write required"
+// @Failure 404 {object} utils.Response "Product not found"
+// @Router /admin/products/{id}/inventory/adjust [post]
+func (s *Server) adjustProductInventory(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid product ID", err)
+		return
+	}
+
+	var req dto.AdjustInventoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request data", err)
+		return
+	}
+
+	movement, err := s.inventoryService.AdjustInventory(uint(id), c.GetUint("user_id"), &req)
+	if err != nil {
+		if errors.Is(err, services.ErrProductNotFound) {
+			utils.NotFoundResponse(c, "Product not found")
+			return
+		}
+		utils.BadRequestResponse(c, "Failed to adjust inventory", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "Inventory adjusted successfully", movement)
+}
+
+// @Summary Get low-stock report
+// @Description List active products whose stock is at or below their low-stock threshold, emptiest first (requires inventory:read)
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} utils.PaginatedResponse{data=[]dto.LowStockProductResponse} "Low-stock products retrieved successfully"
+// @Failure 401 {object} utils.Response "Unauthorized"
+// @Failure 403 {object} utils.Response "Permission inventory:read required"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /admin/inventory/low-stock [get]
+func (s *Server) getLowStockProducts(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	products, meta, err := s.inventoryService.GetLowStockProducts(page, limit)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch low-stock products", err)
+		return
+	}
+
+	utils.PaginatedSuccessResponse(c, "Low-stock products retrieved successfully", products, *meta)
+}
@@ -0,0 +1,143 @@
+package server
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tomimandalaputra/e-commerce-go/internal/dto"
+	"github.com/tomimandalaputra/e-commerce-go/internal/services"
+	"github.com/tomimandalaputra/e-commerce-go/internal/utils"
+)
+
+// @Summary Set up TOTP
+// @Description Generate a new TOTP secret and its otpauth:// provisioning URI to show as a QR code. Two-factor authentication is only turned on after confirming a code at /users/mfa/totp/enable
+// @Tags User
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.Response{data=dto.TOTPSetupResponse} "TOTP secret generated successfully"
+// @Failure 401 {object} utils.Response "Unauthorized"
+// @Failure 409 {object} utils.Response "Two-factor authentication is already enabled"
+// @Router /users/mfa/totp [post]
+func (s *Server) setupTOTP(c *gin.Context) {
+	setup, err := s.mfaService.SetupTOTP(c.GetUint("user_id"))
+	if err != nil {
+		if errors.Is(err, services.ErrMFAAlreadyEnabled) {
+			utils.ConflictResponse(c, "Two-factor authentication is already enabled", err)
+			return
+		}
+
+		utils.InternalServerErrorResponse(c, "Failed to set up two-factor authentication", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "TOTP secret generated successfully", setup)
+}
+
+// @Summary Enable TOTP
+// @Description Turn on two-factor authentication with a code from the authenticator app. Returns recovery codes, which are shown only once
+// @Tags User
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.EnableTOTPRequest true "TOTP code"
+// @Success 200 {object} utils.Response{data=dto.RecoveryCodesResponse} "Two-factor authentication enabled successfully"
+// @Failure 400 {object} utils.Response "Invalid request data, invalid code or TOTP not set up"
+// @Failure 401 {object} utils.Response "Unauthorized"
+// @Failure 409 {object} utils.Response "Two-factor authentication is already enabled"
+// @Router /users/mfa/totp/enable [post]
+func (s *Server) enableTOTP(c *gin.Context) {
+	var req dto.EnableTOTPRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request data", err)
+		return
+	}
+
+	codes, err := s.mfaService.EnableTOTP(c.GetUint("user_id"), &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrMFAAlreadyEnabled):
+			utils.ConflictResponse(c, "Two-factor authentication is already enabled", err)
+		case errors.Is(err, services.ErrMFANotSetUp):
+			utils.BadRequestResponse(c, "Two-factor authentication has not been set up", err)
+		case errors.Is(err, services.ErrInvalidMFACode):
+			utils.BadRequestResponse(c, "Invalid two-factor authentication code", err)
+		default:
+			utils.InternalServerErrorResponse(c, "Failed to enable two-factor authentication", err)
+		}
+		return
+	}
+
+	utils.SuccessResponse(c, "Two-factor authentication enabled successfully", codes)
+}
+
+// @Summary Disable TOTP
+// @Description Turn off two-factor authentication. Requires the password and a TOTP or recovery code
+// @Tags User
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.DisableTOTPRequest true "Password and code"
+// @Success 200 {object} utils.Response "Two-factor authentication disabled successfully"
+// @Failure 400 {object} utils.Response "Invalid request data, incorrect password, invalid code or not enabled"
+// @Failure 401 {object} utils.Response "Unauthorized"
+// @Failure 403 {object} utils.Response "Two-factor authentication is required for this account"
+// @Router /users/mfa/totp/disable [post]
+func (s *Server) disableTOTP(c *gin.Context) {
+	var req dto.DisableTOTPRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request data", err)
+		return
+	}
+
+	if err := s.mfaService.DisableTOTP(c.GetUint("user_id"), &req); err != nil {
+		switch {
+		case errors.Is(err, services.ErrMFANotEnabled):
+			utils.BadRequestResponse(c, "Two-factor authentication is not enabled", err)
+		case errors.Is(err, services.ErrMFARequired):
+			utils.ForbiddenResponse(c, "Two-factor authentication is required for this account")
+		case errors.Is(err, services.ErrIncorrectPassword):
+			utils.BadRequestResponse(c, "Password is incorrect", err)
+		case errors.Is(err, services.ErrInvalidMFACode):
+			utils.BadRequestResponse(c, "Invalid two-factor authentication code", err)
+		default:
+			utils.InternalServerErrorResponse(c, "Failed to disable two-factor authentication", err)
+		}
+		return
+	}
+
+	utils.SuccessResponse(c, "Two-factor authentication disabled successfully", nil)
+}
+
+// @Summary Regenerate recovery codes
+// @Description Replace all recovery codes with new ones. Requires a TOTP code; the new codes are shown only once
+// @Tags User
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.RegenerateRecoveryCodesRequest true "TOTP code"
+// @Success 200 {object} utils.Response{data=dto.RecoveryCodesResponse} "Recovery codes regenerated successfully"
+// @Failure 400 {object} utils.Response "Invalid request data, invalid code or not enabled"
+// @Failure 401 {object} utils.Response "Unauthorized"
+// @Router /users/mfa/recovery-codes [post]
+func (s *Server) regenerateRecoveryCodes(c *gin.Context) {
+	var req dto.RegenerateRecoveryCodesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request data", err)
+		return
+	}
+
+	codes, err := s.mfaService.RegenerateRecoveryCodes(c.GetUint("user_id"), &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrMFANotEnabled):
+			utils.BadRequestResponse(c, "Two-factor authentication is not enabled", err)
+		case errors.Is(err, services.ErrInvalidMFACode):
+			utils.BadRequestResponse(c, "Invalid two-factor authentication code", err)
+		default:
+			utils.InternalServerErrorResponse(c, "Failed to regenerate recovery codes", err)
+		}
+		return
+	}
+
+	utils.SuccessResponse(c, "Recovery codes regenerated successfully", codes)
+}
@@ -1,13 +1,34 @@
 package server
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
 	"strings"
 
 	"github.com/gin-gonic/gin"
-	"github.com/tomimandalaputra/e-commerce-go/internal/models"
+	"github.com/tomimandalaputra/e-commerce-go/internal/services"
 	"github.com/tomimandalaputra/e-commerce-go/internal/utils"
 )
 
+const (
+	idempotencyKeyHeader      = "Idempotency-Key"
+	idempotencyReplayedHeader = "Idempotent-Replayed"
+	maxIdempotencyKeyLength   = 255
+)
+
+// replayedErrorStatuses are the client errors stored for an idempotency key,
+// because retrying the same request would fail the same way. A conflict or a
+// missing email verification can turn out differently on retry.
+var replayedErrorStatuses = map[int]bool{
+	http.StatusBadRequest:      true,
+	http.StatusPaymentRequired: true,
+	http.StatusNotFound:        true,
+}
+
 func (s *Server) authMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
@@ -24,36 +45,174 @@ func (s *Server) authMiddleware() gin.HandlerFunc {
 			return
 		}
 
-		claims, err := utils.ValidateToken(tokenParts[1], s.config.JWT.Secret)
+		claims, err := s.authService.ValidateAccessToken(tokenParts[1])
 		if err != nil {
 			utils.UnauthorizedResponse(c, "Invalid token")
 			c.Abort()
 			return
 		}
 
+		// Tokens without a jti predate revocation and could never be revoked, so they are refused
+		if claims.ID == "" {
+			utils.UnauthorizedResponse(c, "Invalid token")
+			c.Abort()
+			return
+		}
+
+		revoked, err := s.revocationService.IsRevoked(claims.ID)
+		if err != nil {
+			utils.InternalServerErrorResponse(c, "Failed to validate token", err)
+			c.Abort()
+			return
+		}
+
+		if revoked {
+			utils.UnauthorizedResponse(c, "Token has been revoked")
+			c.Abort()
+			return
+		}
+
 		c.Set("user_id", claims.UserID)
 		c.Set("user_email", claims.Email)
-		c.Set("user_role", claims.Role)
 
 		c.Next()
 	}
 }
 
-func (s *Server) adminMiddleware() gin.HandlerFunc {
+// requirePermission only lets through users whose roles grant permission.
+// When the MFA policy requires it, they must also have two-factor
+// authentication enabled. Must run after authMiddleware.
+func (s *Server) requirePermission(permission string) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		role, exists := c.Get("user_role")
-		if !exists {
-			utils.ForbiddenResponse(c, "Forbidden")
+		allowed, err := s.rbacService.HasPermission(c.GetUint("user_id"), permission)
+		if err != nil {
+			utils.InternalServerErrorResponse(c, "Failed to check permissions", err)
 			c.Abort()
 			return
 		}
 
-		if role != string(models.UserRoleAdmin) {
+		if !allowed {
 			utils.ForbiddenResponse(c, "Forbidden")
 			c.Abort()
 			return
 		}
 
+		if s.config.Auth.MFA.RequireForAdmins {
+			enabled, err := s.mfaService.IsEnabled(c.GetUint("user_id"))
+			if err != nil {
+				utils.InternalServerErrorResponse(c, "Failed to check two-factor authentication", err)
+				c.Abort()
+				return
+			}
+
+			if !enabled {
+				utils.ForbiddenResponse(c, "Two-factor authentication must be enabled to use staff endpoints")
+				c.Abort()
+				return
+			}
+		}
+
 		c.Next()
 	}
 }
+
+// idempotencyMiddleware makes a route safe to retry. When the client sends an
+// Idempotency-Key header the first final response is stored per user and
+// replayed for every retry with the same key and body. Reusing a key with a different
+// body is rejected with 409 Conflict. Must run after authMiddleware.
+func (s *Server) idempotencyMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(idempotencyKeyHeader)
+		if key == "" {
+			c.Next()
+			return
+		}
+
+		if len(key) > maxIdempotencyKeyLength {
+			utils.BadRequestResponse(c, "Idempotency-Key is too long", nil)
+			c.Abort()
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			utils.BadRequestResponse(c, "Invalid request body", err)
+			c.Abort()
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		userID := c.GetUint("user_id")
+		method := c.Request.Method
+		path := c.Request.URL.Path
+
+		hash := sha256.New()
+		hash.Write([]byte(method + " " + path + "\n"))
+		hash.Write(body)
+		requestHash := hex.EncodeToString(hash.Sum(nil))
+
+		stored, err := s.idempotencyService.Begin(userID, key, method, path, requestHash)
+		if err != nil {
+			if errors.Is(err, services.ErrIdempotencyKeyMismatch) || errors.Is(err, services.ErrIdempotencyKeyInProgress) {
+				utils.ConflictResponse(c, "Idempotency-Key conflict", err)
+			} else {
+				utils.InternalServerErrorResponse(c, "Failed to process Idempotency-Key", err)
+			}
+			c.Abort()
+			return
+		}
+
+		if stored != nil {
+			c.Header(idempotencyReplayedHeader, "true")
+			c.Data(stored.StatusCode, "application/json; charset=utf-8", []byte(stored.ResponseBody))
+			c.Abort()
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+
+		// Only successes and errors in replayedErrorStatuses are final; for
+		// anything else the client may retry with the same key. The key is also
+		// released when a handler panics, which skips the rest of this function,
+		// so a retry does not get 409 until the key expires.
+		completed := false
+		defer func() {
+			if completed {
+				return
+			}
+
+			if err := s.idempotencyService.Release(userID, key); err != nil {
+				s.logger.Error().Err(err).Str("key", key).Msg("failed to release idempotency key")
+			}
+		}()
+
+		c.Next()
+
+		status := recorder.Status()
+		if (status < http.StatusOK || status >= http.StatusMultipleChoices) && !replayedErrorStatuses[status] {
+			return
+		}
+
+		completed = true
+		if err := s.idempotencyService.Complete(userID, key, status, recorder.body.Bytes()); err != nil {
+			s.logger.Error().Err(err).Str("key", key).Msg("failed to store idempotent response")
+		}
+	}
+}
+
+// responseRecorder captures the response body while still writing it to the client.
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (r *responseRecorder) Write(data []byte) (int, error) {
+	r.body.Write(data)
+	return r.ResponseWriter.Write(data)
+}
+
+func (r *responseRecorder) WriteString(data string) (int, error) {
+	r.body.WriteString(data)
+	return r.ResponseWriter.WriteString(data)
+}
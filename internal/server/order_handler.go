@@ -1,28 +1,65 @@
 package server
 
 import (
+	"errors"
+	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
-	_ "github.com/tomimandalaputra/e-commerce-go/internal/dto"
+	"github.com/tomimandalaputra/e-commerce-go/internal/dto"
+	"github.com/tomimandalaputra/e-commerce-go/internal/models"
+	"github.com/tomimandalaputra/e-commerce-go/internal/services"
 	"github.com/tomimandalaputra/e-commerce-go/internal/utils"
 )
 
 // @Summary Create an order
-// @Description Create an order from the current user's cart
+// @Description Create an order from the current user's cart and authorize its payment
 // @Tags Orders
+// @Accept json
 // @Produce json
 // @Security BearerAuth
+// @Param request body dto.CreateOrderRequest true "Payment details"
+// @Param Idempotency-Key header string false "Unique key to safely retry this request"
 // @Success 201 {object} utils.Response{data=dto.OrderResponse} "Order created successfully"
-// @Failure 400 {object} utils.Response "Cart is empty or insufficient stock"
+// @Failure 400 {object} utils.Response "Invalid request data or cart is empty"
 // @Failure 401 {object} utils.Response "Unauthorized"
+// @Failure 402 {object} utils.Response "Payment declined"
+// @Failure 403 {object} utils.Response "Email address is not verified"
+// @Failure 409 {object} utils.Response "Insufficient stock, another checkout is in progress or Idempotency-Key reused with a different request"
+// @Failure 500 {object} utils.Response "Order could not be created"
 // @Router /orders [post]
 func (s *Server) createOrder(c *gin.Context) {
 	userID := c.GetUint("user_id")
 
-	order, err := s.orderService.CreateOrder(userID)
+	var req dto.CreateOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request data", err)
+		return
+	}
+
+	order, err := s.orderService.CreateOrder(userID, &req)
 	if err != nil {
-		utils.BadRequestResponse(c, "Failed to create order", err)
+		if errors.Is(err, services.ErrPaymentDeclined) {
+			utils.ErrorResponse(c, http.StatusPaymentRequired, "Payment declined", err)
+			return
+		}
+		if errors.Is(err, services.ErrEmailNotVerified) {
+			utils.ForbiddenResponse(c, "Verify your email address before placing an order")
+			return
+		}
+		if errors.Is(err, services.ErrCheckoutInProgress) {
+			utils.ConflictResponse(c, "Another checkout is in progress", err)
+			return
+		}
+		if errors.Is(err, services.ErrInsufficientStock) {
+			utils.ConflictResponse(c, "Insufficient stock", err)
+			return
+		}
+		if errors.Is(err, services.ErrCartEmpty) {
+			utils.BadRequestResponse(c, "Cart is empty", err)
+			return
+		}
+		utils.InternalServerErrorResponse(c, "Failed to create order", err)
 		return
 	}
 
@@ -83,3 +120,116 @@ func (s *Server) getOrder(c *gin.Context) {
 
 	utils.SuccessResponse(c, "Order retrieved successfully", order)
 }
+
+// @Summary Get order status history
+// @Description Retrieve the status timeline of an order. Customers can view their own orders; staff with orders:read can view any order.
+// @Tags Orders
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Order ID"
+// @Success 200 {object} utils.Response{data=[]dto.OrderStatusHistoryResponse} "Order history retrieved successfully"
+// @Failure 400 {object} utils.Response "Invalid order ID"
+// @Failure 401 {object} utils.Response "Unauthorized"
+// @Failure 404 {object} utils.Response "Order not found"
+// @Router /orders/{id}/history [get]
+func (s *Server) getOrderHistory(c *gin.Context) {
+	userID := c.GetUint("user_id")
+	isAdmin, err := s.rbacService.HasPermission(userID, models.PermissionOrdersRead)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to check permissions", err)
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid order ID", err)
+		return
+	}
+
+	history, err := s.orderService.GetOrderHistory(userID, uint(id), isAdmin)
+	if err != nil {
+		if errors.Is(err, services.ErrOrderNotFound) {
+			utils.NotFoundResponse(c, "Order not found")
+			return
+		}
+		utils.InternalServerErrorResponse(c, "Failed to fetch order history", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "Order history retrieved successfully", history)
+}
+
+// @Summary Cancel an order
+// @Description Cancel one of the current user's orders while it is pending or confirmed. Ordered items are returned to stock.
+// @Tags Orders
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Order ID"
+// @Param Idempotency-Key header string false "Unique key to safely retry this request"
+// @Success 200 {object} utils.Response{data=dto.OrderResponse} "Order cancelled successfully"
+// @Failure 400 {object} utils.Response "Invalid order ID or order can no longer be cancelled"
+// @Failure 401 {object} utils.Response "Unauthorized"
+// @Failure 404 {object} utils.Response "Order not found"
+// @Failure 409 {object} utils.Response "Idempotency-Key reused with a different request"
+// @Router /orders/{id}/cancel [post]
+func (s *Server) cancelOrder(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid order ID", err)
+		return
+	}
+
+	order, err := s.orderService.CancelOrder(userID, uint(id))
+	if err != nil {
+		if errors.Is(err, services.ErrOrderNotFound) {
+			utils.NotFoundResponse(c, "Order not found")
+			return
+		}
+		utils.BadRequestResponse(c, "Failed to cancel order", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "Order cancelled successfully", order)
+}
+
+// @Summary Update order status
+// @Description Move an order to a new status (requires orders:update). Only legal transitions are accepted: pending to confirmed or cancelled, confirmed to shipped or cancelled, shipped to delivered. Orders whose payment is still being authorized (pending_payment) can only be cancelled.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Order ID"
+// @Param request body dto.UpdateOrderStatusRequest true "New order status"
+// @Success 200 {object} utils.Response{data=dto.OrderResponse} "Order status updated successfully"
+// @Failure 400 {object} utils.Response "Invalid request data or illegal status transition"
+// @Failure 401 {object} utils.Response "Unauthorized"
+// @Failure 403 {object} utils.Response "Permission orders:update required"
+// @Failure 404 {object} utils.Response "Order not found"
+// @Router /admin/orders/{id}/status [put]
+func (s *Server) updateOrderStatus(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid order ID", err)
+		return
+	}
+
+	var req dto.UpdateOrderStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request data", err)
+		return
+	}
+
+	order, err := s.orderService.UpdateOrderStatus(c.GetUint("user_id"), uint(id), &req)
+	if err != nil {
+		if errors.Is(err, services.ErrOrderNotFound) {
+			utils.NotFoundResponse(c, "Order not found")
+			return
+		}
+		utils.BadRequestResponse(c, "Failed to update order status", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "Order status updated successfully", order)
+}
@@ -9,7 +9,7 @@ import (
 )
 
 // @Summary Create a new category
-// @Description Create a new product category (Admin only)
+// @Description Create a new product category (requires categories:write)
 // @Tags Categories
 // @Accept json
 // @Produce json
@@ -18,7 +18,7 @@ import (
 // @Success 201 {object} utils.Response{data=dto.CategoryResponse} "Category created successfully"
 // @Failure 400 {object} utils.Response "Invalid request data"
 // @Failure 401 {object} utils.Response "Unauthorized"
-// @Failure 403 {object} utils.Response "Admin access required"
+// @Failure 403 {object} utils.Response "Permission categories:write required"
 // @Router /categories [post]
 func (s *Server) createCategory(c *gin.Context) {
 	var req dto.CreateCategoryRequest
@@ -55,7 +55,7 @@ func (s *Server) getCategories(c *gin.Context) {
 }
 
 // @Summary Update a category
-// @Description Update an existing category (Admin only)
+// @Description Update an existing category (requires categories:write)
 // @Tags Categories
 // @Accept json
 // @Produce json
@@ -65,7 +65,7 @@ func (s *Server) getCategories(c *gin.Context) {
 // @Success 200 {object} utils.Response{data=dto.CategoryResponse} "Category updated successfully"
 // @Failure 400 {object} utils.Response "Invalid request data"
 // @Failure 401 {object} utils.Response "Unauthorized"
-// @Failure 403 {object} utils.Response "Admin access required"
+// @Failure 403 {object} utils.Response "Permission categories:write required"
 // @Router /categories/{id} [put]
 func (s *Server) updateCategory(c *gin.Context) {
 	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
@@ -90,14 +90,14 @@ func (s *Server) updateCategory(c *gin.Context) {
 }
 
 // @Summary Delete a category
-// @Description Delete a category (Admin only)
+// @Description Delete a category (requires categories:write)
 // @Tags Categories
 // @Security BearerAuth
 // @Param id path int true "Category ID"
 // @Success 200 {object} utils.Response "Category deleted successfully"
 // @Failure 400 {object} utils.Response "Invalid category ID"
 // @Failure 401 {object} utils.Response "Unauthorized"
-// @Failure 403 {object} utils.Response "Admin access required"
+// @Failure 403 {object} utils.Response "Permission categories:write required"
 // @Router /categories/{id} [delete]
 func (s *Server) deleteCategory(c *gin.Context) {
 	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
@@ -115,7 +115,7 @@ func (s *Server) deleteCategory(c *gin.Context) {
 }
 
 // @Summary Create a new product
-// @Description Create a new product (Admin only)
+// @Description Create a new product (requires products:write)
 // @Tags Products
 // @Accept json
 // @Produce json
@@ -124,7 +124,7 @@ func (s *Server) deleteCategory(c *gin.Context) {
 // @Success 201 {object} utils.Response{data=dto.ProductResponse} "Product created successfully"
 // @Failure 400 {object} utils.Response "Invalid request data"
 // @Failure 401 {object} utils.Response "Unauthorized"
-// @Failure 403 {object} utils.Response "Admin access required"
+// @Failure 403 {object} utils.Response "Permission products:write required"
 // @Router /products [post]
 func (s *Server) createProduct(c *gin.Context) {
 	var req dto.CreateProductRequest
@@ -133,7 +133,7 @@ func (s *Server) createProduct(c *gin.Context) {
 		return
 	}
 
-	product, err := s.productService.CreateProduct(&req)
+	product, err := s.productService.CreateProduct(c.GetUint("user_id"), &req)
 	if err != nil {
 		utils.InternalServerErrorResponse(c, "Failed to create product", err)
 		return
@@ -190,7 +190,7 @@ func (s *Server) getProduct(c *gin.Context) {
 }
 
 // @Summary Update a product
-// @Description Update an existing product (Admin only)
+// @Description Update an existing product (requires products:write)
 // @Tags Products
 // @Accept json
 // @Produce json
@@ -200,7 +200,7 @@ func (s *Server) getProduct(c *gin.Context) {
 // @Success 200 {object} utils.Response{data=dto.ProductResponse} "Product updated successfully"
 // @Failure 400 {object} utils.Response "Invalid request data"
 // @Failure 401 {object} utils.Response "Unauthorized"
-// @Failure 403 {object} utils.Response "Admin access required"
+// @Failure 403 {object} utils.Response "Permission products:write required"
 // @Router /products/{id} [put]
 func (s *Server) updateProduct(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
@@ -215,7 +215,7 @@ func (s *Server) updateProduct(c *gin.Context) {
 		return
 	}
 
-	product, err := s.productService.UpdateProduct(uint(id), &req)
+	product, err := s.productService.UpdateProduct(uint(id), c.GetUint("user_id"), &req)
 	if err != nil {
 		utils.InternalServerErrorResponse(c, "Failed to update product", err)
 		return
@@ -225,14 +225,14 @@ func (s *Server) updateProduct(c *gin.Context) {
 }
 
 // @Summary Delete a product
-// @Description Delete a product (Admin only)
+// @Description Delete a product (requires products:write)
 // @Tags Products
 // @Security BearerAuth
 // @Param id path int true "Product ID"
 // @Success 200 {object} utils.Response "Product deleted successfully"
 // @Failure 400 {object} utils.Response "Invalid product ID"
 // @Failure 401 {object} utils.Response "Unauthorized"
-// @Failure 403 {object} utils.Response "Admin access required"
+// @Failure 403 {object} utils.Response "Permission products:write required"
 // @Router /products/{id} [delete]
 func (s *Server) deleteProduct(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
@@ -250,7 +250,7 @@ func (s *Server) deleteProduct(c *gin.Context) {
 }
 
 // @Summary Upload product image
-// @Description Upload an image for a product (Admin only)
+// @Description Upload an image for a product (requires products:write)
 // @Tags Products
 // @Accept multipart/form-data
 // @Produce json
@@ -260,7 +260,7 @@ func (s *Server) deleteProduct(c *gin.Context) {
 // @Success 200 {object} utils.Response{data=map[string]string} "Image uploaded successfully"
 // @Failure 400 {object} utils.Response "Invalid request or file"
 // @Failure 401 {object} utils.Response "Unauthorized"
-// @Failure 403 {object} utils.Response "Admin access required"
+// @Failure 403 {object} utils.Response "Permission products:write required"
 // @Router /products/{id}/images [post]
 func (s *Server) uploadProductImage(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
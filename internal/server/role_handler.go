@@ -0,0 +1,148 @@
+package server
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tomimandalaputra/e-commerce-go/internal/dto"
+	"github.com/tomimandalaputra/e-commerce-go/internal/services"
+	"github.com/tomimandalaputra/e-commerce-go/internal/utils"
+)
+
+// @Summary Get roles
+// @Description List every staff role with the permissions it grants (requires roles:manage)
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.Response{data=[]dto.RoleResponse} "Roles retrieved successfully"
+// @Failure 401 {object} utils.Response "Unauthorized"
+// @Failure 403 {object} utils.Response "Permission roles:manage required"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /admin/roles [get]
+func (s *Server) getRoles(c *gin.Context) {
+	roles, err := s.rbacService.GetRoles()
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch roles", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "Roles retrieved successfully", roles)
+}
+
+// @Summary Get user roles
+// @Description List the roles assigned to a user (requires roles:manage)
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Success 200 {object} utils.Response{data=[]dto.RoleResponse} "User roles retrieved successfully"
+// @Failure 400 {object} utils.Response "Invalid user ID"
+// @Failure 401 {object} utils.Response "Unauthorized"
+// @Failure 403 {object} utils.Response "Permission roles:manage required"
+// @Failure 404 {object} utils.Response "User not found"
+// @Router /admin/users/{id}/roles [get]
+func (s *Server) getUserRoles(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid user ID", err)
+		return
+	}
+
+	roles, err := s.rbacService.GetUserRoles(uint(id))
+	if err != nil {
+		if errors.Is(err, services.ErrUserNotFound) {
+			utils.NotFoundResponse(c, "User not found")
+			return
+		}
+
+		utils.InternalServerErrorResponse(c, "Failed to fetch user roles", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "User roles retrieved successfully", roles)
+}
+
+// @Summary Assign role
+// @Description Grant a staff role to a user. Returns the roles the user now holds (requires roles:manage)
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Param request body dto.AssignRoleRequest true "Role to assign"
+// @Success 200 {object} utils.Response{data=[]dto.RoleResponse} "Role assigned successfully"
+// @Failure 400 {object} utils.Response "Invalid request data or changing your own roles"
+// @Failure 401 {object} utils.Response "Unauthorized"
+// @Failure 403 {object} utils.Response "Permission roles:manage required"
+// @Failure 404 {object} utils.Response "User or role not found"
+// @Router /admin/users/{id}/roles [post]
+func (s *Server) assignRole(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid user ID", err)
+		return
+	}
+
+	var req dto.AssignRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request data", err)
+		return
+	}
+
+	roles, err := s.rbacService.AssignRole(c.GetUint("user_id"), uint(id), &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrCannotChangeOwnRoles):
+			utils.BadRequestResponse(c, "You cannot change your own roles", err)
+		case errors.Is(err, services.ErrUserNotFound):
+			utils.NotFoundResponse(c, "User not found")
+		case errors.Is(err, services.ErrRoleNotFound):
+			utils.NotFoundResponse(c, "Role not found")
+		default:
+			utils.InternalServerErrorResponse(c, "Failed to assign role", err)
+		}
+		return
+	}
+
+	utils.SuccessResponse(c, "Role assigned successfully", roles)
+}
+
+// @Summary Revoke role
+// @Description Take a staff role away from a user (requires roles:manage)
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Param role path string true "Role name"
+// @Success 200 {object} utils.Response "Role revoked successfully"
+// @Failure 400 {object} utils.Response "Invalid user ID or changing your own roles"
+// @Failure 401 {object} utils.Response "Unauthorized"
+// @Failure 403 {object} utils.Response "Permission roles:manage required"
+// @Failure 404 {object} utils.Response "User or role not found, or role not assigned"
+// @Router /admin/users/{id}/roles/{role} [delete]
+func (s *Server) revokeRole(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid user ID", err)
+		return
+	}
+
+	if err := s.rbacService.RevokeRole(c.GetUint("user_id"), uint(id), c.Param("role")); err != nil {
+		switch {
+		case errors.Is(err, services.ErrCannotChangeOwnRoles):
+			utils.BadRequestResponse(c, "You cannot change your own roles", err)
+		case errors.Is(err, services.ErrUserNotFound):
+			utils.NotFoundResponse(c, "User not found")
+		case errors.Is(err, services.ErrRoleNotFound):
+			utils.NotFoundResponse(c, "Role not found")
+		case errors.Is(err, services.ErrRoleNotAssigned):
+			utils.NotFoundResponse(c, "Role is not assigned to the user")
+		default:
+			utils.InternalServerErrorResponse(c, "Failed to revoke role", err)
+		}
+		return
+	}
+
+	utils.SuccessResponse(c, "Role revoked successfully", nil)
+}
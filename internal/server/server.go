@@ -7,6 +7,7 @@ import (
 	"github.com/rs/zerolog"
 	_ "github.com/tomimandalaputra/e-commerce-go/docs"
 	"github.com/tomimandalaputra/e-commerce-go/internal/config"
+	"github.com/tomimandalaputra/e-commerce-go/internal/models"
 	"github.com/tomimandalaputra/e-commerce-go/internal/services"
 	"gorm.io/gorm"
 
@@ -15,15 +16,22 @@ import (
 )
 
 type Server struct {
-	config         *config.Config
-	db             *gorm.DB
-	logger         *zerolog.Logger
-	authService    *services.AuthService
-	productService *services.ProductService
-	userService    *services.UserService
-	uploadService  *services.UploadService
-	cartService    *services.CartService
-	orderService   *services.OrderService
+	config                *config.Config
+	db                    *gorm.DB
+	logger                *zerolog.Logger
+	authService           *services.AuthService
+	productService        *services.ProductService
+	userService           *services.UserService
+	uploadService         *services.UploadService
+	cartService           *services.CartService
+	orderService          *services.OrderService
+	inventoryService      *services.InventoryService
+	paymentWebhookService *services.PaymentWebhookService
+	idempotencyService    *services.IdempotencyService
+	webhookService        *services.WebhookService
+	revocationService     *services.TokenRevocationService
+	mfaService            *services.MFAService
+	rbacService           *services.RBACService
 }
 
 func New(
@@ -36,17 +44,31 @@ func New(
 	uploadService *services.UploadService,
 	cartService *services.CartService,
 	orderService *services.OrderService,
+	inventoryService *services.InventoryService,
+	paymentWebhookService *services.PaymentWebhookService,
+	idempotencyService *services.IdempotencyService,
+	webhookService *services.WebhookService,
+	revocationService *services.TokenRevocationService,
+	mfaService *services.MFAService,
+	rbacService *services.RBACService,
 ) *Server {
 	return &Server{
-		config:         cfg,
-		db:             db,
-		logger:         logger,
-		authService:    authService,
-		productService: productService,
-		userService:    userService,
-		uploadService:  uploadService,
-		cartService:    cartService,
-		orderService:   orderService,
+		config:                cfg,
+		db:                    db,
+		logger:                logger,
+		authService:           authService,
+		productService:        productService,
+		userService:           userService,
+		uploadService:         uploadService,
+		cartService:           cartService,
+		orderService:          orderService,
+		inventoryService:      inventoryService,
+		paymentWebhookService: paymentWebhookService,
+		idempotencyService:    idempotencyService,
+		webhookService:        webhookService,
+		revocationService:     revocationService,
+		mfaService:            mfaService,
+		rbacService:           rbacService,
 	}
 }
 
@@ -60,6 +82,7 @@ func (s *Server) SetupRoutes() *gin.Engine {
 
 	// Add routes
 	router.GET("/health", s.healthCheck)
+	router.GET("/.well-known/jwks.json", s.getJWKS)
 
 	// Add documentation routes
 	router.GET("/docs/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
@@ -76,9 +99,19 @@ func (s *Server) SetupRoutes() *gin.Engine {
 			auth.POST("/login", s.login)
 			auth.POST("/refresh", s.refreshToken)
 			auth.POST("/logout", s.logout)
+			auth.POST("/verify-email", s.verifyEmail)
+			auth.POST("/resend-verification", s.resendVerification)
+			auth.POST("/forgot-password", s.forgotPassword)
+			auth.POST("/reset-password", s.resetPassword)
+			auth.POST("/mfa/verify", s.verifyMFA)
 
 		}
 
+		webhooks := api.Group("/webhooks")
+		{ //nolint:gocritic // I need this for readability
+			webhooks.POST("/payments/:provider", s.handlePaymentWebhook)
+		}
+
 		protected := api.Group("/")
 		protected.Use(s.authMiddleware())
 		{
@@ -88,25 +121,32 @@ func (s *Server) SetupRoutes() *gin.Engine {
 				userRoutes := users
 				userRoutes.GET("/profile", s.getProfile)
 				userRoutes.PUT("/profile", s.updateProfile)
+				userRoutes.PUT("/password", s.changePassword)
+				userRoutes.GET("/sessions", s.getSessions)
+				userRoutes.DELETE("/sessions/:id", s.revokeSession)
+				userRoutes.POST("/mfa/totp", s.setupTOTP)
+				userRoutes.POST("/mfa/totp/enable", s.enableTOTP)
+				userRoutes.POST("/mfa/totp/disable", s.disableTOTP)
+				userRoutes.POST("/mfa/recovery-codes", s.regenerateRecoveryCodes)
 			}
 
 			// Category routes
 			categories := protected.Group("/categories")
 			{
 				categoryRoutes := categories
-				categoryRoutes.POST("/", s.adminMiddleware(), s.createCategory)
-				categoryRoutes.PUT("/:id", s.adminMiddleware(), s.updateCategory)
-				categoryRoutes.DELETE("/:id", s.adminMiddleware(), s.deleteCategory)
+				categoryRoutes.POST("/", s.requirePermission(models.PermissionCategoriesWrite), s.createCategory)
+				categoryRoutes.PUT("/:id", s.requirePermission(models.PermissionCategoriesWrite), s.updateCategory)
+				categoryRoutes.DELETE("/:id", s.requirePermission(models.PermissionCategoriesWrite), s.deleteCategory)
 			}
 
 			// Product routes
 			products := protected.Group("/products")
 			{
 				productRoutes := products
-				productRoutes.POST("/", s.adminMiddleware(), s.createProduct)
-				productRoutes.PUT("/:id", s.adminMiddleware(), s.updateProduct)
-				productRoutes.DELETE("/:id", s.adminMiddleware(), s.deleteProduct)
-				productRoutes.POST("/:id/images", s.adminMiddleware(), s.uploadProductImage)
+				productRoutes.POST("/", s.requirePermission(models.PermissionProductsWrite), s.createProduct)
+				productRoutes.PUT("/:id", s.requirePermission(models.PermissionProductsWrite), s.updateProduct)
+				productRoutes.DELETE("/:id", s.requirePermission(models.PermissionProductsWrite), s.deleteProduct)
+				productRoutes.POST("/:id/images", s.requirePermission(models.PermissionProductsWrite), s.uploadProductImage)
 			}
 
 			// cart routes
@@ -114,18 +154,48 @@ func (s *Server) SetupRoutes() *gin.Engine {
 			{
 				cartRoutes := cart
 				cartRoutes.GET("/", s.getCart)
-				cartRoutes.POST("/items", s.addToCart)
+				cartRoutes.POST("/items", s.idempotencyMiddleware(), s.addToCart)
 				cartRoutes.PUT("/items/:id", s.updateCartItem)
 				cartRoutes.DELETE("/items/:id", s.removeFromCart)
+				cartRoutes.POST("/checkout", s.beginCheckout)
 			}
 
 			// Order routes
 			orders := protected.Group("/orders")
 			{
 				orderRoutes := orders
-				orderRoutes.POST("/", s.createOrder)
+				orderRoutes.POST("/", s.idempotencyMiddleware(), s.createOrder)
 				orderRoutes.GET("/", s.getOrders)
 				orderRoutes.GET("/:id", s.getOrder)
+				orderRoutes.GET("/:id/history", s.getOrderHistory)
+				orderRoutes.POST("/:id/cancel", s.idempotencyMiddleware(), s.cancelOrder)
+			}
+
+			// Admin routes, each guarded by the permission it needs
+			admin := protected.Group("/admin")
+			{
+				adminRoutes := admin
+				adminRoutes.PUT("/users/:id/status", s.requirePermission(models.PermissionUsersUpdate), s.updateUserStatus)
+				adminRoutes.POST("/users/:id/unlock", s.requirePermission(models.PermissionUsersUnlock), s.unlockUser)
+				adminRoutes.PUT("/orders/:id/status", s.requirePermission(models.PermissionOrdersUpdate), s.updateOrderStatus)
+				adminRoutes.GET("/products/:id/inventory", s.requirePermission(models.PermissionInventoryRead), s.getProductInventory)
+				adminRoutes.POST("/products/:id/inventory/adjust", s.requirePermission(models.PermissionInventoryWrite), s.adjustProductInventory)
+				adminRoutes.GET("/inventory/low-stock", s.requirePermission(models.PermissionInventoryRead), s.getLowStockProducts)
+				adminRoutes.GET("/roles", s.requirePermission(models.PermissionRolesManage), s.getRoles)
+				adminRoutes.GET("/users/:id/roles", s.requirePermission(models.PermissionRolesManage), s.getUserRoles)
+				adminRoutes.POST("/users/:id/roles", s.requirePermission(models.PermissionRolesManage), s.assignRole)
+				adminRoutes.DELETE("/users/:id/roles/:role", s.requirePermission(models.PermissionRolesManage), s.revokeRole)
+
+				webhookRoutes := admin.Group("/webhooks")
+				webhookRoutes.Use(s.requirePermission(models.PermissionWebhooksManage))
+				webhookRoutes.POST("", s.createWebhookSubscription)
+				webhookRoutes.GET("", s.getWebhookSubscriptions)
+				webhookRoutes.GET("/:id", s.getWebhookSubscription)
+				webhookRoutes.PUT("/:id", s.updateWebhookSubscription)
+				webhookRoutes.DELETE("/:id", s.deleteWebhookSubscription)
+				webhookRoutes.GET("/:id/deliveries", s.getWebhookDeliveries)
+				webhookRoutes.GET("/:id/deliveries/:deliveryId", s.getWebhookDelivery)
+				webhookRoutes.POST("/:id/deliveries/:deliveryId/redeliver", s.redeliverWebhook)
 			}
 		}
 
@@ -144,11 +214,18 @@ func (s *Server) healthCheck(c *gin.Context) {
 	})
 }
 
+// getJWKS publishes the token verification keys as a plain JSON Web Key Set,
+// so other services can validate tokens without calling this API.
+func (s *Server) getJWKS(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, s.authService.JWKS())
+}
+
 func (s *Server) corsMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.Header("Access-Control-Allow-Origin", "*")
 		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		c.Header("Access-Control-Allow-Headers", "Content-Type, Authorization, Idempotency-Key")
 
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(http.StatusNoContent)
@@ -0,0 +1,48 @@
+package server
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tomimandalaputra/e-commerce-go/internal/services"
+	"github.com/tomimandalaputra/e-commerce-go/internal/utils"
+)
+
+// @Summary Receive a payment webhook
+// @Description Receive an asynchronous payment notification from a payment provider. The raw body must be signed with HMAC-SHA256 using the configured webhook secret and the hex digest sent in the X-Payment-Signature header. Replayed events are acknowledged without being processed again.
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Param provider path string true "Payment provider name"
+// @Param X-Payment-Signature header string true "Hex encoded HMAC-SHA256 signature of the request body"
+// @Success 200 {object} utils.Response "Webhook processed successfully"
+// @Failure 400 {object} utils.Response "Invalid webhook payload"
+// @Failure 401 {object} utils.Response "Invalid webhook signature"
+// @Failure 404 {object} utils.Response "Unknown payment provider"
+// @Failure 500 {object} utils.Response "Failed to process webhook"
+// @Router /webhooks/payments/{provider} [post]
+func (s *Server) handlePaymentWebhook(c *gin.Context) {
+	payload, err := c.GetRawData()
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid webhook payload", err)
+		return
+	}
+
+	err = s.paymentWebhookService.HandleWebhook(c.Param("provider"), c.GetHeader("X-Payment-Signature"), payload)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrUnknownPaymentProvider):
+			utils.NotFoundResponse(c, "Unknown payment provider")
+		case errors.Is(err, services.ErrInvalidWebhookSignature):
+			utils.UnauthorizedResponse(c, "Invalid webhook signature")
+		case errors.Is(err, services.ErrInvalidWebhookPayload):
+			utils.BadRequestResponse(c, "Invalid webhook payload", err)
+		default:
+			s.logger.Error().Err(err).Str("provider", c.Param("provider")).Msg("failed to process payment webhook")
+			utils.InternalServerErrorResponse(c, "Failed to process webhook", err)
+		}
+		return
+	}
+
+	utils.SuccessResponse(c, "Webhook processed successfully", nil)
+}
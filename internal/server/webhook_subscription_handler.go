@@ -0,0 +1,291 @@
+package server
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tomimandalaputra/e-commerce-go/internal/dto"
+	"github.com/tomimandalaputra/e-commerce-go/internal/services"
+	"github.com/tomimandalaputra/e-commerce-go/internal/utils"
+)
+
+// @Summary Create webhook subscription
+// @Description Register a merchant endpoint to receive signed domain events. Event types can be ORDER_CREATED, ORDER_STATUS_CHANGED, PRODUCT_PRICE_CHANGED, PRODUCT_LOW_STOCK, PRODUCT_OUT_OF_STOCK or "*" for all of them. The signing secret is only returned in this response (requires webhooks:manage)
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.CreateWebhookSubscriptionRequest true "Webhook subscription data"
+// @Success 201 {object} utils.Response{data=dto.WebhookSubscriptionResponse} "Webhook subscription created successfully"
+// @Failure 400 {object} utils.Response "Invalid request data or unknown event type"
+// @Failure 401 {object} utils.Response "Unauthorized"
+// @Failure 403 {object} utils.Response "Permission webhooks:manage required"
+// @Router /admin/webhooks [post]
+func (s *Server) createWebhookSubscription(c *gin.Context) {
+	var req dto.CreateWebhookSubscriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request data", err)
+		return
+	}
+
+	subscription, err := s.webhookService.CreateSubscription(&req)
+	if err != nil {
+		if errors.Is(err, services.ErrUnknownEventType) {
+			utils.BadRequestResponse(c, "Unknown event type", err)
+			return
+		}
+		utils.InternalServerErrorResponse(c, "Failed to create webhook subscription", err)
+		return
+	}
+
+	utils.CreatedResponse(c, "Webhook subscription created successfully", subscription)
+}
+
+// @Summary Get webhook subscriptions
+// @Description Retrieve a paginated list of webhook subscriptions (requires webhooks:manage)
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} utils.PaginatedResponse{data=[]dto.WebhookSubscriptionResponse} "Webhook subscriptions retrieved successfully"
+// @Failure 401 {object} utils.Response "Unauthorized"
+// @Failure 403 {object} utils.Response "Permission webhooks:manage required"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /admin/webhooks [get]
+func (s *Server) getWebhookSubscriptions(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	subscriptions, meta, err := s.webhookService.GetSubscriptions(page, limit)
+	if err != nil {
+		utils.InternalServerErrorResponse(c, "Failed to fetch webhook subscriptions", err)
+		return
+	}
+
+	utils.PaginatedSuccessResponse(c, "Webhook subscriptions retrieved successfully", subscriptions, *meta)
+}
+
+// @Summary Get webhook subscription
+// @Description Retrieve a webhook subscription by ID (requires webhooks:manage)
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Webhook subscription ID"
+// @Success 200 {object} utils.Response{data=dto.WebhookSubscriptionResponse} "Webhook subscription retrieved successfully"
+// @Failure 400 {object} utils.Response "Invalid webhook subscription ID"
+// @Failure 401 {object} utils.Response "Unauthorized"
+// @Failure 403 {object} utils.Response "Permission webhooks:manage required"
+// @Failure 404 {object} utils.Response "Webhook subscription not found"
+// @Router /admin/webhooks/{id} [get]
+func (s *Server) getWebhookSubscription(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid webhook subscription ID", err)
+		return
+	}
+
+	subscription, err := s.webhookService.GetSubscription(uint(id))
+	if err != nil {
+		if errors.Is(err, services.ErrWebhookSubscriptionNotFound) {
+			utils.NotFoundResponse(c, "Webhook subscription not found")
+			return
+		}
+		utils.InternalServerErrorResponse(c, "Failed to fetch webhook subscription", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "Webhook subscription retrieved successfully", subscription)
+}
+
+// @Summary Update webhook subscription
+// @Description Change the URL, event types or active state of a webhook subscription (requires webhooks:manage)
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Webhook subscription ID"
+// @Param request body dto.UpdateWebhookSubscriptionRequest true "Webhook subscription data"
+// @Success 200 {object} utils.Response{data=dto.WebhookSubscriptionResponse} "Webhook subscription updated successfully"
+// @Failure 400 {object} utils.Response "Invalid request data or unknown event type"
+// @Failure 401 {object} utils.Response "Unauthorized"
+// @Failure 403 {object} utils.Response "Permission webhooks:manage required"
+// @Failure 404 {object} utils.Response "Webhook subscription not found"
+// @Router /admin/webhooks/{id} [put]
+func (s *Server) updateWebhookSubscription(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid webhook subscription ID", err)
+		return
+	}
+
+	var req dto.UpdateWebhookSubscriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequestResponse(c, "Invalid request data", err)
+		return
+	}
+
+	subscription, err := s.webhookService.UpdateSubscription(uint(id), &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrWebhookSubscriptionNotFound):
+			utils.NotFoundResponse(c, "Webhook subscription not found")
+		case errors.Is(err, services.ErrUnknownEventType):
+			utils.BadRequestResponse(c, "Unknown event type", err)
+		default:
+			utils.InternalServerErrorResponse(c, "Failed to update webhook subscription", err)
+		}
+		return
+	}
+
+	utils.SuccessResponse(c, "Webhook subscription updated successfully", subscription)
+}
+
+// @Summary Delete webhook subscription
+// @Description Delete a webhook subscription; pending deliveries to it are abandoned (requires webhooks:manage)
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Webhook subscription ID"
+// @Success 200 {object} utils.Response "Webhook subscription deleted successfully"
+// @Failure 400 {object} utils.Response "Invalid webhook subscription ID"
+// @Failure 401 {object} utils.Response "Unauthorized"
+// @Failure 403 {object} utils.Response "Permission webhooks:manage required"
+// @Failure 404 {object} utils.Response "Webhook subscription not found"
+// @Router /admin/webhooks/{id} [delete]
+func (s *Server) deleteWebhookSubscription(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid webhook subscription ID", err)
+		return
+	}
+
+	if err := s.webhookService.DeleteSubscription(uint(id)); err != nil {
+		if errors.Is(err, services.ErrWebhookSubscriptionNotFound) {
+			utils.NotFoundResponse(c, "Webhook subscription not found")
+			return
+		}
+		utils.InternalServerErrorResponse(c, "Failed to delete webhook subscription", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "Webhook subscription deleted successfully", nil)
+}
+
+// @Summary Get webhook deliveries
+// @Description Retrieve the paginated delivery log of a webhook subscription, newest first (requires webhooks:manage)
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Webhook subscription ID"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} utils.PaginatedResponse{data=[]dto.WebhookDeliveryResponse} "Webhook deliveries retrieved successfully"
+// @Failure 400 {object} utils.Response "Invalid webhook subscription ID"
+// @Failure 401 {object} utils.Response "Unauthorized"
+// @Failure 403 {object} utils.Response "Permission webhooks:manage required"
+// @Failure 404 {object} utils.Response "Webhook subscription not found"
+// @Router /admin/webhooks/{id}/deliveries [get]
+func (s *Server) getWebhookDeliveries(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid webhook subscription ID", err)
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	deliveries, meta, err := s.webhookService.GetDeliveries(uint(id), page, limit)
+	if err != nil {
+		if errors.Is(err, services.ErrWebhookSubscriptionNotFound) {
+			utils.NotFoundResponse(c, "Webhook subscription not found")
+			return
+		}
+		utils.InternalServerErrorResponse(c, "Failed to fetch webhook deliveries", err)
+		return
+	}
+
+	utils.PaginatedSuccessResponse(c, "Webhook deliveries retrieved successfully", deliveries, *meta)
+}
+
+// @Summary Get webhook delivery
+// @Description Retrieve a webhook delivery with every attempt made for it (requires webhooks:manage)
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Webhook subscription ID"
+// @Param deliveryId path int true "Webhook delivery ID"
+// @Success 200 {object} utils.Response{data=dto.WebhookDeliveryResponse} "Webhook delivery retrieved successfully"
+// @Failure 400 {object} utils.Response "Invalid webhook subscription or delivery ID"
+// @Failure 401 {object} utils.Response "Unauthorized"
+// @Failure 403 {object} utils.Response "Permission webhooks:manage required"
+// @Failure 404 {object} utils.Response "Webhook delivery not found"
+// @Router /admin/webhooks/{id}/deliveries/{deliveryId} [get]
+func (s *Server) getWebhookDelivery(c *gin.Context) {
+	id, deliveryID, ok := parseWebhookDeliveryParams(c)
+	if !ok {
+		return
+	}
+
+	delivery, err := s.webhookService.GetDelivery(id, deliveryID)
+	if err != nil {
+		if errors.Is(err, services.ErrWebhookDeliveryNotFound) {
+			utils.NotFoundResponse(c, "Webhook delivery not found")
+			return
+		}
+		utils.InternalServerErrorResponse(c, "Failed to fetch webhook delivery", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "Webhook delivery retrieved successfully", delivery)
+}
+
+// @Summary Redeliver webhook
+// @Description Queue a webhook delivery to be sent again immediately with a fresh retry budget (requires webhooks:manage)
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Webhook subscription ID"
+// @Param deliveryId path int true "Webhook delivery ID"
+// @Success 200 {object} utils.Response{data=dto.WebhookDeliveryResponse} "Webhook delivery queued for redelivery"
+// @Failure 400 {object} utils.Response "Invalid webhook subscription or delivery ID"
+// @Failure 401 {object} utils.Response "Unauthorized"
+// @Failure 403 {object} utils.Response "Permission webhooks:manage required"
+// @Failure 404 {object} utils.Response "Webhook delivery not found"
+// @Router /admin/webhooks/{id}/deliveries/{deliveryId}/redeliver [post]
+func (s *Server) redeliverWebhook(c *gin.Context) {
+	id, deliveryID, ok := parseWebhookDeliveryParams(c)
+	if !ok {
+		return
+	}
+
+	delivery, err := s.webhookService.Redeliver(id, deliveryID)
+	if err != nil {
+		if errors.Is(err, services.ErrWebhookDeliveryNotFound) {
+			utils.NotFoundResponse(c, "Webhook delivery not found")
+			return
+		}
+		utils.InternalServerErrorResponse(c, "Failed to redeliver webhook", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "Webhook delivery queued for redelivery", delivery)
+}
+
+func parseWebhookDeliveryParams(c *gin.Context) (uint, uint64, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid webhook subscription ID", err)
+		return 0, 0, false
+	}
+
+	deliveryID, err := strconv.ParseUint(c.Param("deliveryId"), 10, 64)
+	if err != nil {
+		utils.BadRequestResponse(c, "Invalid webhook delivery ID", err)
+		return 0, 0, false
+	}
+
+	return uint(id), deliveryID, true
+}
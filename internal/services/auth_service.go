@@ -1,33 +1,98 @@
 package services
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"time"
 
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
 	"github.com/tomimandalaputra/e-commerce-go/internal/config"
 	"github.com/tomimandalaputra/e-commerce-go/internal/dto"
 	"github.com/tomimandalaputra/e-commerce-go/internal/events"
+	"github.com/tomimandalaputra/e-commerce-go/internal/events/schema"
 	"github.com/tomimandalaputra/e-commerce-go/internal/models"
 	"github.com/tomimandalaputra/e-commerce-go/internal/utils"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+var (
+	// ErrInvalidVerificationToken is returned when an email verification token is unknown, used or expired.
+	ErrInvalidVerificationToken = errors.New("invalid or expired verification token")
+	// ErrInvalidResetToken is returned when a password reset token is unknown, used or expired.
+	ErrInvalidResetToken = errors.New("invalid or expired password reset token")
+	// ErrRefreshTokenReused is returned when an already rotated refresh token is presented again.
+	ErrRefreshTokenReused = errors.New("refresh token reuse detected")
+	// ErrInvalidCredentials is returned when the email or password is wrong.
+	ErrInvalidCredentials = errors.New("invalid credentials")
+	// ErrTooManyLoginAttempts is matched by LoginThrottledError.
+	ErrTooManyLoginAttempts = errors.New("too many failed login attempts")
+	// ErrInvalidMFAChallenge is returned when an MFA challenge token is unknown, used or expired.
+	ErrInvalidMFAChallenge = errors.New("invalid or expired MFA challenge")
+)
+
+// Reasons reported in LOGIN_FAILED events
+const (
+	loginFailureInvalidPassword = "invalid_password"
+	loginFailureInvalidMFACode  = "invalid_mfa_code"
+)
+
+// LoginThrottledError is returned while an account is locked or waiting out the
+// delay after a failed login, or an IP address has failed too many logins.
+// RetryAfter is when to try again.
+type LoginThrottledError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LoginThrottledError) Error() string {
+	return ErrTooManyLoginAttempts.Error()
+}
+
+func (e *LoginThrottledError) Is(target error) bool {
+	return target == ErrTooManyLoginAttempts
+}
+
+// maxUserAgentLength matches the size of refresh_tokens.user_agent
+const maxUserAgentLength = 512
+
+// ClientInfo describes the device a session is issued to.
+type ClientInfo struct {
+	UserAgent string
+	IPAddress string
+}
+
 type AuthService struct {
-	db             *gorm.DB
-	config         *config.Config
-	eventPublisher events.Publisher
+	db                *gorm.DB
+	config            *config.Config
+	keys              *utils.KeySet
+	revocationService *TokenRevocationService
+	mfaService        *MFAService
 }
 
-func NewAuthService(db *gorm.DB, cfg *config.Config, eventPublisher events.Publisher) *AuthService {
+func NewAuthService(db *gorm.DB, cfg *config.Config, keys *utils.KeySet, revocationService *TokenRevocationService, mfaService *MFAService) *AuthService {
 	return &AuthService{
-		db:             db,
-		config:         cfg,
-		eventPublisher: eventPublisher,
+		db:                db,
+		config:            cfg,
+		keys:              keys,
+		revocationService: revocationService,
+		mfaService:        mfaService,
 	}
 }
 
-func (s *AuthService) Register(req *dto.RegisterRequest) (*dto.AuthResponse, error) {
+// ValidateAccessToken checks an access token issued by this service. Refresh
+// tokens are rejected.
+func (s *AuthService) ValidateAccessToken(token string) (*utils.Claims, error) {
+	return utils.ValidateToken(&s.config.JWT, s.keys, token, utils.TokenTypeAccess)
+}
+
+// JWKS returns the public keys other services can verify tokens with.
+func (s *AuthService) JWKS() *utils.JWKS {
+	return s.keys.JWKS()
+}
+
+func (s *AuthService) Register(req *dto.RegisterRequest, client *ClientInfo) (*dto.AuthResponse, error) {
 	// Check if user exists
 	var existingUser models.User
 	if err := s.db.Where("email  = ?", req.Email).First(&existingUser).Error; err == nil {
@@ -47,96 +112,780 @@ func (s *AuthService) Register(req *dto.RegisterRequest) (*dto.AuthResponse, err
 		LastName:  req.LastName,
 		Phone:     req.Phone,
 		IsActive:  true,
-		Role:      models.UserRoleCustomer,
 	}
-	if err := s.db.Create(&user).Error; err != nil {
+
+	var authResponse *dto.AuthResponse
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&user).Error; err != nil {
+			return err
+		}
+
+		// create a cart
+		cart := models.Cart{UserID: user.ID}
+		if err := tx.Create(&cart).Error; err != nil {
+			return fmt.Errorf("unable to create cart: %w", err)
+		}
+
+		if err := events.Enqueue(tx, schema.UserRegisteredV1{
+			UserID:    user.ID,
+			Email:     user.Email,
+			FirstName: user.FirstName,
+			LastName:  user.LastName,
+		}, ""); err != nil {
+			return err
+		}
+
+		if err := s.issueVerificationToken(tx, &user); err != nil {
+			return err
+		}
+
+		// generate token
+		var err error
+		authResponse, err = s.generateAuthResponse(tx, &user, client, "")
+		return err
+	})
+
+	if err != nil {
 		return nil, err
 	}
-	// create a cart
-	cart := models.Cart{UserID: user.ID}
-	if err := s.db.Create(&cart).Error; err != nil {
-		fmt.Println("Unable to create cart")
-	}
-
-	// generate token
-	return s.generateAuthResponse(&user)
 
+	return authResponse, nil
 }
 
-func (s *AuthService) Login(req *dto.LoginRequest) (*dto.AuthResponse, error) {
+// Login checks the password under the login policy: IP addresses with too
+// many recent failures are refused, consecutive wrong passwords slow the
+// response down and eventually lock the account for a while. Accounts with
+// two-factor authentication get an MFA challenge token instead of a token
+// pair, to be redeemed with VerifyMFA.
+func (s *AuthService) Login(req *dto.LoginRequest, client *ClientInfo) (*dto.LoginResponse, error) {
+	policy := &s.config.Auth.Login
+
 	var user models.User
+	found := true
 	if err := s.db.Where("email = ? AND is_active = ?", req.Email, true).First(&user).Error; err != nil {
-		return nil, errors.New("invalid credentials")
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+		found = false
+	}
+
+	// The attempt is stored as failed before anything is checked and only
+	// marked succeeded once it passes, so concurrent requests from one address
+	// all count towards its limit
+	attempt := models.LoginAttempt{Email: req.Email, IPAddress: client.IPAddress}
+	if found {
+		attempt.UserID = &user.ID
+	}
+	if err := s.db.Create(&attempt).Error; err != nil {
+		return nil, err
+	}
+
+	var ipFailures int64
+	if err := s.db.Model(&models.LoginAttempt{}).
+		Where("ip_address = ? AND succeeded = ? AND created_at > ?", client.IPAddress, false, time.Now().Add(-policy.IPWindow)).
+		Count(&ipFailures).Error; err != nil {
+		return nil, err
+	}
+
+	if ipFailures > int64(policy.IPMaxFailedAttempts) {
+		return nil, &LoginThrottledError{RetryAfter: policy.IPWindow}
+	}
+
+	if !found {
+		return nil, ErrInvalidCredentials
+	}
+
+	if err := s.db.Transaction(func(tx *gorm.DB) error {
+		return s.reserveLoginAttempt(tx, &user)
+	}); err != nil {
+		return nil, err
 	}
 
 	if !utils.CheckPassword(req.Password, user.Password) {
-		return nil, errors.New("invalid credentials")
+		if err := s.db.Transaction(func(tx *gorm.DB) error {
+			return s.recordLoginFailure(tx, &user, client, loginFailureInvalidPassword)
+		}); err != nil {
+			return nil, err
+		}
+		return nil, ErrInvalidCredentials
+	}
+
+	// The failed attempt counter is only reset once the second step passes,
+	// so a known password does not give unlimited guesses at the code. The
+	// hold on the next attempt is lifted so the code can be entered right away.
+	if user.TOTPEnabledAt != nil {
+		if err := s.db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Model(&attempt).Update("succeeded", true).Error; err != nil {
+				return err
+			}
+			return tx.Model(&user).Update("login_not_before", nil).Error
+		}); err != nil {
+			return nil, err
+		}
+
+		return s.createMFAChallenge(&user)
+	}
+
+	var authResponse *dto.AuthResponse
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&attempt).Update("succeeded", true).Error; err != nil {
+			return err
+		}
+
+		var err error
+		authResponse, err = s.completeLogin(tx, &user, client)
+		return err
+	})
+
+	if err != nil {
+		return nil, err
 	}
 
-	return s.generateAuthResponse(&user)
+	return &dto.LoginResponse{AuthResponse: authResponse}, nil
 }
 
-func (s *AuthService) RefreshToken(req *dto.RefreshTokenRequest) (*dto.AuthResponse, error) {
-	claims, err := utils.ValidateToken(req.RefreshToken, s.config.JWT.Secret)
+// VerifyMFA completes a login with the MFA challenge token from Login and a
+// TOTP or recovery code. Wrong codes count towards the account lockout, and a
+// challenge is burnt after too many of them.
+func (s *AuthService) VerifyMFA(req *dto.VerifyMFARequest, client *ClientInfo) (*dto.AuthResponse, error) {
+	var authResponse *dto.AuthResponse
+	var invalidCode bool
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var challenge models.MFAChallenge
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("token_hash IN ? AND used_at IS NULL AND expires_at > ?",
+				tokenHashes(&s.config.Auth, req.MFAToken), time.Now()).
+			First(&challenge).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrInvalidMFAChallenge
+			}
+			return err
+		}
+
+		var user models.User
+		if err := tx.Where("is_active = ?", true).First(&user, challenge.UserID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrInvalidMFAChallenge
+			}
+			return err
+		}
+
+		if err := s.reserveLoginAttempt(tx, &user); err != nil {
+			return err
+		}
+
+		valid, err := s.mfaService.verifyCode(tx, &user, req.Code)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Create(&models.LoginAttempt{
+			Email:     user.Email,
+			UserID:    &user.ID,
+			IPAddress: client.IPAddress,
+			Succeeded: valid,
+		}).Error; err != nil {
+			return err
+		}
+
+		if !valid {
+			updates := map[string]any{"attempts": challenge.Attempts + 1}
+			if challenge.Attempts+1 >= s.config.Auth.MFA.ChallengeMaxAttempts {
+				updates["used_at"] = time.Now()
+			}
+
+			if err := tx.Model(&challenge).Updates(updates).Error; err != nil {
+				return err
+			}
+
+			invalidCode = true
+			return s.recordLoginFailure(tx, &user, client, loginFailureInvalidMFACode)
+		}
+
+		if err := tx.Model(&challenge).Update("used_at", time.Now()).Error; err != nil {
+			return err
+		}
+
+		authResponse, err = s.completeLogin(tx, &user, client)
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if invalidCode {
+		return nil, ErrInvalidMFACode
+	}
+
+	return authResponse, nil
+}
+
+// createMFAChallenge stores a short-lived challenge for the second login step.
+// Only the hash of the token is kept.
+func (s *AuthService) createMFAChallenge(user *models.User) (*dto.LoginResponse, error) {
+	token, err := utils.GenerateToken(32)
+	if err != nil {
+		return nil, err
+	}
+
+	challenge := models.MFAChallenge{
+		UserID:    user.ID,
+		TokenHash: utils.HashToken(s.config.Auth.TokenHashSecret, token),
+		ExpiresAt: time.Now().Add(s.config.Auth.MFA.ChallengeTTL),
+	}
+
+	if err := s.db.Create(&challenge).Error; err != nil {
+		return nil, err
+	}
+
+	return &dto.LoginResponse{
+		MFARequired:       true,
+		MFAToken:          token,
+		MFATokenExpiresAt: &challenge.ExpiresAt,
+	}, nil
+}
+
+// completeLogin clears the failed attempt counter, including the attempt
+// reserved for this login, and issues a token pair for a new session.
+func (s *AuthService) completeLogin(tx *gorm.DB, user *models.User, client *ClientInfo) (*dto.AuthResponse, error) {
+	if err := tx.Model(user).Updates(map[string]any{
+		"failed_login_attempts": 0,
+		"login_not_before":      nil,
+		"locked_until":          nil,
+	}).Error; err != nil {
+		return nil, err
+	}
+
+	if err := events.Enqueue(tx, schema.UserLoggedInV2{
+		UserID: user.ID,
+		Email:  user.Email,
+	}, ""); err != nil {
+		return nil, err
+	}
+
+	return s.generateAuthResponse(tx, user, client, "")
+}
+
+// RefreshToken rotates a refresh token. Presenting a token that was already
+// rotated means it was copied, so the whole family is revoked: the attacker and
+// the legitimate client both have to sign in again.
+func (s *AuthService) RefreshToken(req *dto.RefreshTokenRequest, client *ClientInfo) (*dto.AuthResponse, error) {
+	claims, err := utils.ValidateToken(&s.config.JWT, s.keys, req.RefreshToken, utils.TokenTypeRefresh)
 	if err != nil {
 		return nil, errors.New("invalid refresh token")
 	}
 
-	var refreshToken models.RefreshToken
-	if err := s.db.Where("token = ? AND expires_at > ?", req.RefreshToken, time.Now()).First(&refreshToken).Error; err != nil {
-		return nil, errors.New("refresh token not found or expired")
+	var authResponse *dto.AuthResponse
+	reused := false
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		var refreshToken models.RefreshToken
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("token_hash = ? AND expires_at > ?", utils.HashRefreshToken(req.RefreshToken), time.Now()).
+			First(&refreshToken).Error; err != nil {
+			return errors.New("refresh token not found or expired")
+		}
+
+		if refreshToken.RotatedAt != nil {
+			reused = true
+			return s.revokeReusedFamily(tx, &refreshToken, client)
+		}
+
+		var user models.User
+		if err := tx.Where("is_active = ?", true).First(&user, claims.UserID).Error; err != nil {
+			return errors.New("user not found")
+		}
+
+		if err := tx.Model(&refreshToken).Update("rotated_at", time.Now()).Error; err != nil {
+			return err
+		}
+
+		var err error
+		authResponse, err = s.generateAuthResponse(tx, &user, client, refreshToken.FamilyID)
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if reused {
+		return nil, ErrRefreshTokenReused
 	}
 
-	var user models.User
-	if err := s.db.First(&user, claims.UserID).Error; err != nil {
-		return nil, errors.New("user not found")
+	return authResponse, nil
+}
+
+// reserveLoginAttempt counts an attempt against the account before its
+// password or code is checked and holds off the next one, doubling the hold
+// with every consecutive failure. The user row is locked while doing so, so
+// concurrent requests cannot each get a guess while the account is locked or
+// delayed. A successful login clears the counter again.
+func (s *AuthService) reserveLoginAttempt(tx *gorm.DB, user *models.User) error {
+	policy := &s.config.Auth.Login
+
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(user, user.ID).Error; err != nil {
+		return err
+	}
+
+	if user.LockedUntil != nil && user.LockedUntil.After(time.Now()) {
+		return &LoginThrottledError{RetryAfter: time.Until(*user.LockedUntil)}
+	}
+
+	if user.LoginNotBefore != nil && user.LoginNotBefore.After(time.Now()) {
+		return &LoginThrottledError{RetryAfter: time.Until(*user.LoginNotBefore)}
+	}
+
+	failedAttempts := user.FailedLoginAttempts + 1
+	notBefore := time.Now().Add(utils.Backoff(failedAttempts, policy.DelayBase, policy.MaxDelay))
+
+	if err := tx.Model(user).Updates(map[string]any{
+		"failed_login_attempts": failedAttempts,
+		"login_not_before":      notBefore,
+	}).Error; err != nil {
+		return err
+	}
+
+	user.FailedLoginAttempts = failedAttempts
+	user.LoginNotBefore = &notBefore
+	return nil
+}
+
+// recordLoginFailure reports a wrong password or MFA code whose attempt was
+// reserved with reserveLoginAttempt, and locks the account once the policy
+// limit is reached.
+//
+// Only these failures publish LOGIN_FAILED, so the events are bounded by the
+// lockout. Unknown emails and attempts on locked accounts cost an attacker
+// nothing to repeat and are only kept in login_attempts.
+func (s *AuthService) recordLoginFailure(tx *gorm.DB, user *models.User, client *ClientInfo, reason string) error {
+	policy := &s.config.Auth.Login
+
+	// Lock the row so concurrent failures cannot miss the lockout
+	var locked models.User
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&locked, user.ID).Error; err != nil {
+		return err
 	}
 
-	s.db.Delete(&refreshToken)
+	failedAttempts := locked.FailedLoginAttempts
+	if failedAttempts >= policy.MaxFailedAttempts {
+		lockedUntil := time.Now().Add(policy.LockoutDuration)
+
+		if err := tx.Model(&locked).Updates(map[string]any{
+			"failed_login_attempts": 0,
+			"login_not_before":      nil,
+			"locked_until":          lockedUntil,
+		}).Error; err != nil {
+			return err
+		}
 
-	return s.generateAuthResponse(&user)
+		if err := events.Enqueue(tx, schema.AccountLockedV1{
+			UserID:      user.ID,
+			Email:       user.Email,
+			IPAddress:   client.IPAddress,
+			LockedUntil: lockedUntil,
+		}, ""); err != nil {
+			return err
+		}
+	}
+
+	return events.Enqueue(tx, schema.LoginFailedV1{
+		Email:          user.Email,
+		UserID:         &user.ID,
+		IPAddress:      client.IPAddress,
+		Reason:         reason,
+		FailedAttempts: failedAttempts,
+	}, "")
 }
 
+// PurgeLoginAttempts deletes login attempts older than the retention period
+// and MFA challenges that have expired.
+func (s *AuthService) PurgeLoginAttempts() (int64, error) {
+	result := s.db.Where("created_at < ?", time.Now().Add(-s.config.Auth.Login.AttemptRetention)).Delete(&models.LoginAttempt{})
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	challenges := s.db.Where("expires_at < ?", time.Now()).Delete(&models.MFAChallenge{})
+	return result.RowsAffected + challenges.RowsAffected, challenges.Error
+}
+
+// StartLoginAttemptPurger purges old login attempts every interval until ctx is cancelled.
+func (s *AuthService) StartLoginAttemptPurger(ctx context.Context, interval time.Duration, logger *zerolog.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			purged, err := s.PurgeLoginAttempts()
+			if err != nil {
+				logger.Error().Err(err).Msg("failed to purge login attempts")
+				continue
+			}
+
+			if purged > 0 {
+				logger.Info().Int64("purged", purged).Msg("purged old login attempts")
+			}
+		}
+	}
+}
+
+// Logout revokes the session the refresh token belongs to, including its
+// access tokens.
 func (s *AuthService) Logout(refreshToken string) error {
-	return s.db.Where("token = ?", refreshToken).Delete(&models.RefreshToken{}).Error
+	var token models.RefreshToken
+	if err := s.db.Where("token_hash = ?", utils.HashRefreshToken(refreshToken)).First(&token).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := s.revocationService.RevokeFamily(tx, token.FamilyID); err != nil {
+			return err
+		}
+
+		return tx.Where("family_id = ?", token.FamilyID).Delete(&models.RefreshToken{}).Error
+	})
 }
 
-func (s *AuthService) generateAuthResponse(user *models.User) (*dto.AuthResponse, error) {
-	accessToken, refreshToken, err := utils.GenerateTokenPair(
+func (s *AuthService) revokeReusedFamily(tx *gorm.DB, token *models.RefreshToken, client *ClientInfo) error {
+	if err := s.revocationService.RevokeFamily(tx, token.FamilyID); err != nil {
+		return err
+	}
+
+	if err := tx.Where("family_id = ?", token.FamilyID).Delete(&models.RefreshToken{}).Error; err != nil {
+		return err
+	}
+
+	return events.Enqueue(tx, schema.RefreshTokenReusedV1{
+		UserID:    token.UserID,
+		FamilyID:  token.FamilyID,
+		TokenID:   token.ID,
+		UserAgent: client.UserAgent,
+		IPAddress: client.IPAddress,
+	}, "")
+}
+
+// VerifyEmail redeems an email verification token. Each token works once.
+func (s *AuthService) VerifyEmail(req *dto.VerifyEmailRequest) error {
+	hashes := tokenHashes(&s.config.Auth, req.Token)
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		var token models.UserToken
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("token_hash IN ? AND purpose = ? AND used_at IS NULL AND expires_at > ?",
+				hashes, models.UserTokenPurposeEmailVerification, time.Now()).
+			First(&token).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrInvalidVerificationToken
+			}
+			return err
+		}
+
+		now := time.Now()
+		if err := tx.Model(&token).Updates(map[string]any{
+			"used_at":          now,
+			"token_ciphertext": nil,
+		}).Error; err != nil {
+			return err
+		}
+
+		var user models.User
+		if err := tx.First(&user, token.UserID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrInvalidVerificationToken
+			}
+			return err
+		}
+
+		if user.EmailVerifiedAt != nil {
+			return nil
+		}
+
+		if err := tx.Model(&user).Update("email_verified_at", now).Error; err != nil {
+			return err
+		}
+
+		return events.Enqueue(tx, schema.UserEmailVerifiedV1{
+			UserID: user.ID,
+			Email:  user.Email,
+		}, "")
+	})
+}
+
+// ResendVerification emails a new verification token and invalidates earlier
+// ones. It reports success for unknown or already verified addresses too, so
+// it cannot be used to find out which emails have an account.
+func (s *AuthService) ResendVerification(req *dto.ResendVerificationRequest) error {
+	var user models.User
+	if err := s.db.Where("email = ? AND is_active = ?", req.Email, true).First(&user).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	if user.EmailVerifiedAt != nil {
+		return nil
+	}
+
+	var recent int64
+	if err := s.db.Model(&models.UserToken{}).
+		Where("user_id = ? AND purpose = ? AND created_at > ?",
+			user.ID, models.UserTokenPurposeEmailVerification, time.Now().Add(-s.config.Auth.VerificationResendCooldown)).
+		Count(&recent).Error; err != nil {
+		return err
+	}
+
+	if recent > 0 {
+		return nil
+	}
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.UserToken{}).
+			Where("user_id = ? AND purpose = ? AND used_at IS NULL", user.ID, models.UserTokenPurposeEmailVerification).
+			Updates(map[string]any{
+				"used_at":          time.Now(),
+				"token_ciphertext": nil,
+			}).Error; err != nil {
+			return err
+		}
+
+		return s.issueVerificationToken(tx, &user)
+	})
+}
+
+// ForgotPassword emails a password reset link. Like ResendVerification it
+// reports success for unknown addresses, and requests over the per-account
+// rate limit are dropped silently for the same reason.
+func (s *AuthService) ForgotPassword(req *dto.ForgotPasswordRequest, requestedIP string) error {
+	var user models.User
+	if err := s.db.Where("email = ? AND is_active = ?", req.Email, true).First(&user).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	var recent int64
+	if err := s.db.Model(&models.PasswordResetToken{}).
+		Where("user_id = ? AND created_at > ?", user.ID, time.Now().Add(-s.config.Auth.PasswordResetRateWindow)).
+		Count(&recent).Error; err != nil {
+		return err
+	}
+
+	if recent >= int64(s.config.Auth.PasswordResetRateLimit) {
+		return nil
+	}
+
+	token, err := utils.GenerateToken(32)
+	if err != nil {
+		return err
+	}
+
+	// Like verification tokens, the worker decrypts the token to email it and then clears it
+	ciphertext, err := utils.Encrypt(s.config.Auth.TokenEncryptionKey, token)
+	if err != nil {
+		return err
+	}
+
+	resetToken := models.PasswordResetToken{
+		UserID:          user.ID,
+		TokenHash:       utils.HashToken(s.config.Auth.TokenHashSecret, token),
+		TokenCiphertext: &ciphertext,
+		RequestedIP:     requestedIP,
+		ExpiresAt:       time.Now().Add(s.config.Auth.PasswordResetTTL),
+	}
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&resetToken).Error; err != nil {
+			return err
+		}
+
+		return events.Enqueue(tx, schema.PasswordResetRequestedV2{
+			UserID:    user.ID,
+			Email:     user.Email,
+			FirstName: user.FirstName,
+			TokenID:   resetToken.ID,
+			ExpiresAt: resetToken.ExpiresAt,
+		}, "")
+	})
+}
+
+// ResetPassword sets a new password with a reset token. Every outstanding
+// reset token and refresh token of the user is revoked, signing out all sessions.
+func (s *AuthService) ResetPassword(req *dto.ResetPasswordRequest) error {
+	hashes := tokenHashes(&s.config.Auth, req.Token)
+
+	hashedPassword, err := utils.HashPassword(req.Password)
+	if err != nil {
+		return err
+	}
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		var resetToken models.PasswordResetToken
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("token_hash IN ? AND used_at IS NULL AND expires_at > ?", hashes, time.Now()).
+			First(&resetToken).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrInvalidResetToken
+			}
+			return err
+		}
+
+		var user models.User
+		if err := tx.Where("is_active = ?", true).First(&user, resetToken.UserID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrInvalidResetToken
+			}
+			return err
+		}
+
+		updates := map[string]any{
+			"password":              hashedPassword,
+			"failed_login_attempts": 0,
+			"login_not_before":      nil,
+			"locked_until":          nil,
+		}
+
+		// The reset link was delivered to the inbox, which proves ownership of the address
+		if user.EmailVerifiedAt == nil {
+			updates["email_verified_at"] = time.Now()
+		}
+
+		if err := tx.Model(&user).Updates(updates).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&models.PasswordResetToken{}).
+			Where("user_id = ? AND used_at IS NULL", user.ID).
+			Updates(map[string]any{
+				"used_at":          time.Now(),
+				"token_ciphertext": nil,
+			}).Error; err != nil {
+			return err
+		}
+
+		if err := s.revocationService.RevokeUser(tx, user.ID); err != nil {
+			return err
+		}
+
+		if err := tx.Where("user_id = ?", user.ID).Delete(&models.RefreshToken{}).Error; err != nil {
+			return err
+		}
+
+		return events.Enqueue(tx, schema.UserPasswordResetV1{
+			UserID: user.ID,
+			Email:  user.Email,
+		}, "")
+	})
+}
+
+// issueVerificationToken stores a new verification token and queues the email
+// that delivers it. Only the hash of the token is kept.
+func (s *AuthService) issueVerificationToken(tx *gorm.DB, user *models.User) error {
+	token, err := utils.GenerateToken(32)
+	if err != nil {
+		return err
+	}
+
+	// The worker decrypts the token to email it and then clears it, so the
+	// raw token never travels in the event
+	ciphertext, err := utils.Encrypt(s.config.Auth.TokenEncryptionKey, token)
+	if err != nil {
+		return err
+	}
+
+	userToken := models.UserToken{
+		UserID:          user.ID,
+		Purpose:         models.UserTokenPurposeEmailVerification,
+		TokenHash:       utils.HashToken(s.config.Auth.TokenHashSecret, token),
+		TokenCiphertext: &ciphertext,
+		ExpiresAt:       time.Now().Add(s.config.Auth.EmailVerificationTTL),
+	}
+
+	if err := tx.Create(&userToken).Error; err != nil {
+		return err
+	}
+
+	return events.Enqueue(tx, schema.UserVerificationRequestedV2{
+		UserID:    user.ID,
+		Email:     user.Email,
+		FirstName: user.FirstName,
+		TokenID:   userToken.ID,
+		ExpiresAt: userToken.ExpiresAt,
+	}, "")
+}
+
+// generateAuthResponse issues a token pair. The refresh token joins familyID,
+// or starts a new family (a new session) when it is empty.
+func (s *AuthService) generateAuthResponse(db *gorm.DB, user *models.User, client *ClientInfo, familyID string) (*dto.AuthResponse, error) {
+	tokens, err := utils.GenerateTokenPair(
 		&s.config.JWT,
+		s.keys,
 		user.ID,
 		user.Email,
-		string(user.Role),
 	)
 	if err != nil {
 		return nil, err
 	}
 
-	refreshTokenModel := models.RefreshToken{
-		UserID:    user.ID,
-		Token:     refreshToken,
-		ExpiresAt: time.Now().Add(s.config.JWT.RefreshTokenExpires),
+	if familyID == "" {
+		familyID = uuid.NewString()
 	}
 
-	s.db.Create(&refreshTokenModel)
+	refreshTokenModel := models.RefreshToken{
+		UserID:               user.ID,
+		TokenHash:            utils.HashRefreshToken(tokens.RefreshToken),
+		FamilyID:             familyID,
+		UserAgent:            truncate(client.UserAgent, maxUserAgentLength),
+		IPAddress:            client.IPAddress,
+		ExpiresAt:            time.Now().Add(s.config.JWT.RefreshTokenExpires),
+		AccessTokenID:        tokens.AccessTokenID,
+		AccessTokenExpiresAt: tokens.AccessTokenExpiresAt,
+	}
 
-	err = s.eventPublisher.Publish("USER_LOGGED_IN", user, map[string]string{})
-	if err != nil {
-		return nil, fmt.Errorf("unable to publish user login event: %w", err)
+	if err := db.Create(&refreshTokenModel).Error; err != nil {
+		return nil, err
 	}
 
 	return &dto.AuthResponse{
 		User: dto.UserResponse{
-			ID:        user.ID,
-			Email:     user.Email,
-			FirstName: user.FirstName,
-			LastName:  user.LastName,
-			Phone:     user.Phone,
-			Role:      string(user.Role),
-			IsActive:  user.IsActive,
+			ID:            user.ID,
+			Email:         user.Email,
+			FirstName:     user.FirstName,
+			LastName:      user.LastName,
+			Phone:         user.Phone,
+			IsActive:      user.IsActive,
+			EmailVerified: user.EmailVerifiedAt != nil,
+			MFAEnabled:    user.TOTPEnabledAt != nil,
 		},
-		AccessToken:  accessToken,
-		RefreshToken: refreshToken,
+		AccessToken:  tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
 	}, nil
 
 }
+
+// tokenHashes returns the hashes a stored token can be found under. While
+// TokenHashSecret is being rotated, tokens issued under the previous secret still match.
+func tokenHashes(authConfig *config.AuthConfig, token string) []string {
+	hashes := []string{utils.HashToken(authConfig.TokenHashSecret, token)}
+	if previous := authConfig.PreviousTokenHashSecret; previous != "" {
+		hashes = append(hashes, utils.HashToken(previous, token))
+	}
+	return hashes
+}
+
+func truncate(value string, length int) string {
+	if len(value) <= length {
+		return value
+	}
+	return value[:length]
+}
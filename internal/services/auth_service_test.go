@@ -0,0 +1,112 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/tomimandalaputra/e-commerce-go/internal/config"
+	"github.com/tomimandalaputra/e-commerce-go/internal/dto"
+	"github.com/tomimandalaputra/e-commerce-go/internal/events/schema"
+	"github.com/tomimandalaputra/e-commerce-go/internal/models"
+	"github.com/tomimandalaputra/e-commerce-go/internal/utils"
+)
+
+// TestLoginConcurrentGuesses fires parallel wrong passwords at one account and
+// checks that only one of them gets to check its password.
+func TestLoginConcurrentGuesses(t *testing.T) {
+	db := openTestDB(t)
+
+	const guesses = 20
+
+	password, err := utils.HashPassword("correct horse battery staple")
+	if err != nil {
+		t.Fatalf("failed to hash password: %v", err)
+	}
+
+	user := models.User{
+		Email:     fmt.Sprintf("login-%s@example.com", uuid.New().String()[:8]),
+		Password:  password,
+		FirstName: "Login",
+		LastName:  "Target",
+		IsActive:  true,
+	}
+	if err := db.Create(&user).Error; err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+
+	t.Cleanup(func() {
+		db.Where("event_type IN ? AND (envelope->'payload'->>'user_id')::int = ?",
+			[]string{schema.LoginFailed, schema.AccountLocked}, user.ID).Delete(&models.OutboxEvent{})
+		db.Where("email = ?", user.Email).Delete(&models.LoginAttempt{})
+		db.Unscoped().Delete(&models.User{}, user.ID)
+	})
+
+	authService := NewAuthService(db, &config.Config{
+		Auth: config.AuthConfig{
+			Login: config.LoginPolicy{
+				MaxFailedAttempts:   5,
+				LockoutDuration:     time.Hour,
+				IPMaxFailedAttempts: guesses * 2,
+				IPWindow:            time.Hour,
+				DelayBase:           time.Minute,
+				MaxDelay:            time.Hour,
+			},
+		},
+	}, nil, nil, nil)
+
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		checked   int
+		throttled int
+		failures  []error
+	)
+
+	start := make(chan struct{})
+	for i := range guesses {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-start
+
+			_, err := authService.Login(&dto.LoginRequest{Email: user.Email, Password: fmt.Sprintf("guess-%d", i)},
+				&ClientInfo{IPAddress: "192.0.2.1"})
+
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case errors.Is(err, ErrInvalidCredentials):
+				checked++
+			case errors.Is(err, ErrTooManyLoginAttempts):
+				throttled++
+			default:
+				failures = append(failures, err)
+			}
+		}(i)
+	}
+
+	close(start)
+	wg.Wait()
+
+	for _, err := range failures {
+		t.Errorf("unexpected login result: %v", err)
+	}
+
+	if checked != 1 || throttled != guesses-1 {
+		t.Errorf("checked passwords = %d, throttled = %d, want 1 and %d", checked, throttled, guesses-1)
+	}
+
+	var final models.User
+	if err := db.First(&final, user.ID).Error; err != nil {
+		t.Fatalf("failed to reload user: %v", err)
+	}
+
+	if final.FailedLoginAttempts != 1 {
+		t.Errorf("failed login attempts = %d, want 1", final.FailedLoginAttempts)
+	}
+}
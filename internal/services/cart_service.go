@@ -9,11 +9,15 @@ import (
 )
 
 type CartService struct {
-	db *gorm.DB
+	db               *gorm.DB
+	inventoryService *InventoryService
 }
 
-func NewCartService(db *gorm.DB) *CartService {
-	return &CartService{db: db}
+func NewCartService(db *gorm.DB, inventoryService *InventoryService) *CartService {
+	return &CartService{
+		db:               db,
+		inventoryService: inventoryService,
+	}
 }
 
 func (s *CartService) GetCart(userID uint) (*dto.CartResponse, error) {
@@ -25,7 +29,7 @@ func (s *CartService) GetCart(userID uint) (*dto.CartResponse, error) {
 		return nil, err
 	}
 
-	return s.convertToCartResponse(&cart), nil
+	return s.convertToCartResponse(s.db, &cart)
 }
 
 func (s *CartService) AddToCart(userID uint, req *dto.AddToCartRequest) (*dto.CartResponse, error) {
@@ -38,8 +42,13 @@ func (s *CartService) AddToCart(userID uint, req *dto.AddToCartRequest) (*dto.Ca
 			return errors.New("product not found")
 		}
 
-		if product.Stock < req.Quantity {
-			return errors.New("insufficient stock")
+		available, err := s.availableFor(tx, &product, userID)
+		if err != nil {
+			return err
+		}
+
+		if available < req.Quantity {
+			return ErrInsufficientStock
 		}
 
 		// Get or create cart
@@ -75,8 +84,8 @@ func (s *CartService) AddToCart(userID uint, req *dto.AddToCartRequest) (*dto.Ca
 				cartItem.Quantity += req.Quantity
 			}
 
-			if cartItem.Quantity > product.Stock {
-				return errors.New("insufficient stock")
+			if cartItem.Quantity > available {
+				return ErrInsufficientStock
 			}
 
 			if err := tx.Save(&cartItem).Error; err != nil {
@@ -86,15 +95,15 @@ func (s *CartService) AddToCart(userID uint, req *dto.AddToCartRequest) (*dto.Ca
 
 		// Fetch updated cart with preloads
 		var updatedCart models.Cart
-		err := tx.Preload("CartItems.Product.Category").
+		err = tx.Preload("CartItems.Product.Category").
 			Preload("CartItems.Product.Images").
 			Where("user_id = ?", userID).First(&updatedCart).Error
 		if err != nil {
 			return err
 		}
 
-		cartResponse = s.convertToCartResponse(&updatedCart)
-		return nil
+		cartResponse, err = s.convertToCartResponse(tx, &updatedCart)
+		return err
 	})
 
 	if err != nil {
@@ -120,8 +129,13 @@ func (s *CartService) UpdateCartItem(userID, itemID uint, req *dto.UpdateCartIte
 			return errors.New("product not found")
 		}
 
-		if product.Stock < req.Quantity {
-			return errors.New("insufficient stock")
+		available, err := s.availableFor(tx, &product, userID)
+		if err != nil {
+			return err
+		}
+
+		if available < req.Quantity {
+			return ErrInsufficientStock
 		}
 
 		cartItem.Quantity = req.Quantity
@@ -137,8 +151,8 @@ func (s *CartService) UpdateCartItem(userID, itemID uint, req *dto.UpdateCartIte
 			return err
 		}
 
-		cartResponse = s.convertToCartResponse(&updatedCart)
-		return nil
+		cartResponse, err = s.convertToCartResponse(tx, &updatedCart)
+		return err
 	})
 
 	if err != nil {
@@ -162,7 +176,27 @@ func (s *CartService) RemoveFromCart(userID, itemID uint) error {
 	})
 }
 
-func (s *CartService) convertToCartResponse(cart *models.Cart) *dto.CartResponse {
+// availableFor returns the stock of a product that is not reserved by other customers.
+func (s *CartService) availableFor(tx *gorm.DB, product *models.Product, userID uint) (int, error) {
+	reserved, err := s.inventoryService.ReservedQuantities(tx, []uint{product.ID}, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	return product.Stock - reserved[product.ID], nil
+}
+
+func (s *CartService) convertToCartResponse(db *gorm.DB, cart *models.Cart) (*dto.CartResponse, error) {
+	productIDs := make([]uint, len(cart.CartItems))
+	for i := range cart.CartItems {
+		productIDs[i] = cart.CartItems[i].ProductID
+	}
+
+	reserved, err := s.inventoryService.ReservedQuantities(db, productIDs, 0)
+	if err != nil {
+		return nil, err
+	}
+
 	cartItems := make([]dto.CartItemResponse, len(cart.CartItems)) // memory allocation
 	var total float64
 
@@ -191,6 +225,7 @@ func (s *CartService) convertToCartResponse(cart *models.Cart) *dto.CartResponse
 				Description: cart.CartItems[i].Product.Description,
 				Price:       cart.CartItems[i].Product.Price,
 				Stock:       cart.CartItems[i].Product.Stock,
+				Available:   max(cart.CartItems[i].Product.Stock-reserved[cart.CartItems[i].ProductID], 0),
 				SKU:         cart.CartItems[i].Product.SKU,
 				IsActive:    cart.CartItems[i].Product.IsActive,
 				Category: dto.CategoryResponse{
@@ -211,5 +246,5 @@ func (s *CartService) convertToCartResponse(cart *models.Cart) *dto.CartResponse
 		UserID:    cart.UserID,
 		CartItems: cartItems,
 		Total:     total,
-	}
+	}, nil
 }
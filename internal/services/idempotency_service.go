@@ -0,0 +1,120 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/tomimandalaputra/e-commerce-go/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	// ErrIdempotencyKeyMismatch is returned when a key is reused with a different request.
+	ErrIdempotencyKeyMismatch = errors.New("idempotency key was already used with a different request")
+	// ErrIdempotencyKeyInProgress is returned when the original request for a key has not finished yet.
+	ErrIdempotencyKeyInProgress = errors.New("a request with this idempotency key is still being processed")
+)
+
+type IdempotencyService struct {
+	db  *gorm.DB
+	ttl time.Duration
+}
+
+func NewIdempotencyService(db *gorm.DB, ttl time.Duration) *IdempotencyService {
+	return &IdempotencyService{
+		db:  db,
+		ttl: ttl,
+	}
+}
+
+// Begin claims an idempotency key for a request. It returns nil when the caller
+// should process the request, or the stored record when a completed response
+// must be replayed instead.
+func (s *IdempotencyService) Begin(userID uint, key, method, path, requestHash string) (*models.IdempotencyKey, error) {
+	// Expired keys can be reused as if they were never seen
+	if err := s.db.Where("user_id = ? AND key = ? AND expires_at <= ?", userID, key, time.Now()).
+		Delete(&models.IdempotencyKey{}).Error; err != nil {
+		return nil, err
+	}
+
+	record := models.IdempotencyKey{
+		UserID:      userID,
+		Key:         key,
+		Method:      method,
+		Path:        path,
+		RequestHash: requestHash,
+		ExpiresAt:   time.Now().Add(s.ttl),
+	}
+
+	result := s.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&record)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	if result.RowsAffected == 1 {
+		return nil, nil
+	}
+
+	var existing models.IdempotencyKey
+	if err := s.db.Where("user_id = ? AND key = ?", userID, key).First(&existing).Error; err != nil {
+		return nil, err
+	}
+
+	if existing.RequestHash != requestHash {
+		return nil, ErrIdempotencyKeyMismatch
+	}
+
+	if existing.StatusCode == 0 {
+		return nil, ErrIdempotencyKeyInProgress
+	}
+
+	return &existing, nil
+}
+
+// Complete stores the response produced for a claimed key.
+func (s *IdempotencyService) Complete(userID uint, key string, statusCode int, responseBody []byte) error {
+	return s.db.Model(&models.IdempotencyKey{}).
+		Where("user_id = ? AND key = ?", userID, key).
+		Updates(map[string]any{
+			"status_code":   statusCode,
+			"response_body": string(responseBody),
+		}).Error
+}
+
+// Release forgets a claimed key so the client can retry, used when the request failed unexpectedly.
+func (s *IdempotencyService) Release(userID uint, key string) error {
+	return s.db.Where("user_id = ? AND key = ?", userID, key).Delete(&models.IdempotencyKey{}).Error
+}
+
+// PurgeExpired deletes the keys whose TTL has passed. Begin only clears an
+// expired key when the same key comes back, so the others are left to this.
+func (s *IdempotencyService) PurgeExpired() (int64, error) {
+	result := s.db.Where("expires_at <= ?", time.Now()).Delete(&models.IdempotencyKey{})
+	return result.RowsAffected, result.Error
+}
+
+// StartPurger purges expired idempotency keys every interval until ctx is cancelled.
+func (s *IdempotencyService) StartPurger(ctx context.Context, interval time.Duration, logger *zerolog.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			purged, err := s.PurgeExpired()
+			if err != nil {
+				logger.Error().Err(err).Msg("failed to purge idempotency keys")
+				continue
+			}
+
+			if purged > 0 {
+				logger.Info().Int64("purged", purged).Msg("purged expired idempotency keys")
+			}
+		}
+	}
+}
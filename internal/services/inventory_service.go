@@ -0,0 +1,479 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/tomimandalaputra/e-commerce-go/internal/dto"
+	"github.com/tomimandalaputra/e-commerce-go/internal/events/schema"
+	"github.com/tomimandalaputra/e-commerce-go/internal/models"
+	"github.com/tomimandalaputra/e-commerce-go/internal/utils"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	// ErrInsufficientStock is returned when a product does not have enough stock left.
+	ErrInsufficientStock = errors.New("insufficient stock")
+	// ErrProductNotFound is returned when a product does not exist.
+	ErrProductNotFound = errors.New("product not found")
+)
+
+type InventoryService struct {
+	db             *gorm.DB
+	reservationTTL time.Duration
+}
+
+func NewInventoryService(db *gorm.DB, reservationTTL time.Duration) *InventoryService {
+	return &InventoryService{
+		db:             db,
+		reservationTTL: reservationTTL,
+	}
+}
+
+// ReserveCart holds stock for every item in the user's cart for the reservation TTL.
+// Calling it again replaces the user's previous reservations and restarts the timer.
+func (s *InventoryService) ReserveCart(userID uint) (*dto.CheckoutResponse, error) {
+	var checkoutResponse *dto.CheckoutResponse
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var cart models.Cart
+		if err := tx.Preload("CartItems.Product").Where("user_id = ?", userID).First(&cart).Error; err != nil {
+			return errors.New("cart not found")
+		}
+
+		if len(cart.CartItems) == 0 {
+			return errors.New("cart is empty")
+		}
+
+		if err := s.releaseUserReservations(tx, userID); err != nil {
+			return err
+		}
+
+		// Lock product rows in a consistent order so concurrent checkouts cannot deadlock
+		sort.Slice(cart.CartItems, func(i, j int) bool {
+			return cart.CartItems[i].ProductID < cart.CartItems[j].ProductID
+		})
+
+		expiresAt := time.Now().Add(s.reservationTTL)
+		reservations := make([]dto.StockReservationResponse, len(cart.CartItems))
+
+		for i := range cart.CartItems {
+			cartItem := &cart.CartItems[i]
+
+			var product models.Product
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&product, cartItem.ProductID).Error; err != nil {
+				return errors.New("product not found")
+			}
+
+			reserved, err := s.ReservedQuantities(tx, []uint{product.ID}, userID)
+			if err != nil {
+				return err
+			}
+
+			if product.Stock-reserved[product.ID] < cartItem.Quantity {
+				return fmt.Errorf("%w for product: %s", ErrInsufficientStock, product.Name)
+			}
+
+			reservation := models.StockReservation{
+				UserID:    userID,
+				ProductID: product.ID,
+				Quantity:  cartItem.Quantity,
+				Status:    models.ReservationStatusActive,
+				ExpiresAt: expiresAt,
+			}
+			if err := tx.Create(&reservation).Error; err != nil {
+				return err
+			}
+
+			reservations[i] = dto.StockReservationResponse{
+				ID:          reservation.ID,
+				ProductID:   product.ID,
+				ProductName: product.Name,
+				Quantity:    reservation.Quantity,
+				ExpiresAt:   reservation.ExpiresAt,
+			}
+		}
+
+		checkoutResponse = &dto.CheckoutResponse{
+			Reservations: reservations,
+			ExpiresAt:    expiresAt,
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return checkoutResponse, nil
+}
+
+// ReservedQuantities returns the quantity held by active, unexpired reservations
+// per product. Reservations owned by excludeUserID are not counted; pass 0 to
+// count every reservation.
+func (s *InventoryService) ReservedQuantities(db *gorm.DB, productIDs []uint, excludeUserID uint) (map[uint]int, error) {
+	reserved := make(map[uint]int, len(productIDs))
+	if len(productIDs) == 0 {
+		return reserved, nil
+	}
+
+	var rows []struct {
+		ProductID uint
+		Quantity  int
+	}
+
+	if err := s.activeReservations(db, excludeUserID).
+		Select("product_id, SUM(quantity) AS quantity").
+		Where("product_id IN ?", productIDs).
+		Group("product_id").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		reserved[row.ProductID] = row.Quantity
+	}
+
+	return reserved, nil
+}
+
+// StockChange describes why stock is changing so it can be written to the inventory ledger.
+type StockChange struct {
+	Reason      models.InventoryMovementReason
+	ReferenceID string
+	ActorID     *uint
+	Note        string
+}
+
+// DecrementStock takes quantity out of a product's stock for a user's order and
+// returns the product as it is after the update.
+// Stock reserved by other customers is not available; the update is a single
+// conditional statement so concurrent checkouts can never oversell.
+//
+// The product row is locked first. ReserveCart adds reservations under that
+// lock without updating the row, so an UPDATE merely waiting for the lock
+// would re-check its condition against a reserved sum read before the
+// reservation was committed. Once the lock is held, the UPDATE below is a new
+// statement and sees every committed reservation.
+func (s *InventoryService) DecrementStock(tx *gorm.DB, productID, userID uint, quantity int, change *StockChange) (*models.Product, error) {
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&models.Product{}, productID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrProductNotFound
+		}
+		return nil, err
+	}
+
+	reservedByOthers := s.activeReservations(tx, userID).
+		Select("COALESCE(SUM(quantity), 0)").
+		Where("product_id = ?", productID)
+
+	var product models.Product
+	result := tx.Model(&product).
+		Clauses(clause.Returning{Columns: []clause.Column{
+			{Name: "id"}, {Name: "name"}, {Name: "sku"}, {Name: "stock"}, {Name: "low_stock_threshold"},
+		}}).
+		Where("id = ? AND stock - (?) >= ?", productID, reservedByOthers, quantity).
+		UpdateColumn("stock", gorm.Expr("stock - ?", quantity))
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return nil, ErrInsufficientStock
+	}
+
+	if err := s.recordMovement(tx, productID, -quantity, product.Stock, change); err != nil {
+		return nil, err
+	}
+
+	return &product, nil
+}
+
+// StockAlert returns the event to publish when taking quantity out of stock
+// for an order left the product at its current stock, or nil when no
+// threshold was crossed by this change.
+func (s *InventoryService) StockAlert(product *models.Product, quantity int, orderID uint) schema.Event {
+	previous := product.Stock + quantity
+	alert := schema.ProductStockAlertV1{
+		ProductID:         product.ID,
+		Name:              product.Name,
+		SKU:               product.SKU,
+		Stock:             product.Stock,
+		LowStockThreshold: product.LowStockThreshold,
+		OrderID:           orderID,
+	}
+
+	switch {
+	case product.Stock == 0 && previous > 0:
+		return schema.ProductOutOfStockV1{ProductStockAlertV1: alert}
+	case product.Stock <= product.LowStockThreshold && previous > product.LowStockThreshold:
+		return schema.ProductLowStockV1{ProductStockAlertV1: alert}
+	default:
+		return nil
+	}
+}
+
+// GetLowStockProducts returns active products at or below their low-stock
+// threshold, emptiest first.
+func (s *InventoryService) GetLowStockProducts(page, limit int) ([]dto.LowStockProductResponse, *utils.PaginationMeta, error) {
+	if page < 1 {
+		page = 1
+	}
+
+	if limit < 1 {
+		limit = 10
+	}
+
+	if limit > 100 {
+		limit = 100
+	}
+
+	offset := (page - 1) * limit
+	var products []models.Product
+	var total int64
+
+	lowStock := s.db.Model(&models.Product{}).
+		Where("is_active = ? AND stock <= low_stock_threshold", true)
+
+	if err := lowStock.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, nil, err
+	}
+
+	if err := lowStock.Session(&gorm.Session{}).
+		Order("stock ASC, id ASC").
+		Offset(offset).Limit(limit).
+		Find(&products).Error; err != nil {
+		return nil, nil, err
+	}
+
+	productIDs := make([]uint, len(products))
+	for i := range products {
+		productIDs[i] = products[i].ID
+	}
+
+	reserved, err := s.ReservedQuantities(s.db, productIDs, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	response := make([]dto.LowStockProductResponse, len(products))
+	for i := range products {
+		response[i] = dto.LowStockProductResponse{
+			ID:                products[i].ID,
+			Name:              products[i].Name,
+			SKU:               products[i].SKU,
+			Stock:             products[i].Stock,
+			Available:         max(products[i].Stock-reserved[products[i].ID], 0),
+			LowStockThreshold: products[i].LowStockThreshold,
+			OutOfStock:        products[i].Stock == 0,
+		}
+	}
+
+	totalPages := int((total + int64(limit) - 1) / int64(limit))
+	meta := &utils.PaginationMeta{
+		Page:       page,
+		Limit:      limit,
+		Total:      total,
+		TotalPages: totalPages,
+	}
+
+	return response, meta, nil
+}
+
+// AdjustStock changes a product's stock by delta and records the movement.
+// Stock can never go below zero.
+func (s *InventoryService) AdjustStock(tx *gorm.DB, productID uint, delta int, change *StockChange) error {
+	if delta == 0 {
+		return nil
+	}
+
+	var product models.Product
+	result := tx.Model(&product).
+		Clauses(clause.Returning{Columns: []clause.Column{{Name: "stock"}}}).
+		Where("id = ? AND stock + ? >= 0", productID, delta).
+		UpdateColumn("stock", gorm.Expr("stock + ?", delta))
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrInsufficientStock
+	}
+
+	return s.recordMovement(tx, productID, delta, product.Stock, change)
+}
+
+// AdjustInventory applies a manual stock correction on behalf of an admin.
+func (s *InventoryService) AdjustInventory(productID, actorID uint, req *dto.AdjustInventoryRequest) (*dto.InventoryMovementResponse, error) {
+	var movement models.InventoryMovement
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var product models.Product
+		if err := tx.First(&product, productID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrProductNotFound
+			}
+			return err
+		}
+
+		if err := s.AdjustStock(tx, productID, req.Delta, &StockChange{
+			Reason:      models.InventoryMovementReason(req.Reason),
+			ReferenceID: req.ReferenceID,
+			ActorID:     &actorID,
+			Note:        req.Note,
+		}); err != nil {
+			return err
+		}
+
+		return tx.Where("product_id = ?", productID).Order("id DESC").First(&movement).Error
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	response := s.convertToMovementResponse(&movement)
+	return &response, nil
+}
+
+// GetMovements returns the stock ledger of a product, newest first.
+func (s *InventoryService) GetMovements(productID uint, page, limit int) ([]dto.InventoryMovementResponse, *utils.PaginationMeta, error) {
+	if page < 1 {
+		page = 1
+	}
+
+	if limit < 1 {
+		limit = 10
+	}
+
+	if limit > 100 {
+		limit = 100
+	}
+
+	var count int64
+	if err := s.db.Model(&models.Product{}).Where("id = ?", productID).Count(&count).Error; err != nil {
+		return nil, nil, err
+	}
+
+	if count == 0 {
+		return nil, nil, ErrProductNotFound
+	}
+
+	offset := (page - 1) * limit
+	var movements []models.InventoryMovement
+	var total int64
+
+	s.db.Model(&models.InventoryMovement{}).Where("product_id = ?", productID).Count(&total)
+
+	if err := s.db.Where("product_id = ?", productID).
+		Order("created_at DESC, id DESC").
+		Offset(offset).Limit(limit).
+		Find(&movements).Error; err != nil {
+		return nil, nil, err
+	}
+
+	response := make([]dto.InventoryMovementResponse, len(movements))
+	for i := range movements {
+		response[i] = s.convertToMovementResponse(&movements[i])
+	}
+
+	totalPages := int((total + int64(limit) - 1) / int64(limit))
+	meta := &utils.PaginationMeta{
+		Page:       page,
+		Limit:      limit,
+		Total:      total,
+		TotalPages: totalPages,
+	}
+
+	return response, meta, nil
+}
+
+// ConsumeReservations marks the user's active reservations as used by an order.
+func (s *InventoryService) ConsumeReservations(tx *gorm.DB, userID, orderID uint) error {
+	return tx.Model(&models.StockReservation{}).
+		Where("user_id = ? AND status = ?", userID, models.ReservationStatusActive).
+		Updates(map[string]any{
+			"status":   models.ReservationStatusConsumed,
+			"order_id": orderID,
+		}).Error
+}
+
+// ReleaseExpiredReservations releases every active reservation past its expiry.
+func (s *InventoryService) ReleaseExpiredReservations() (int64, error) {
+	result := s.db.Model(&models.StockReservation{}).
+		Where("status = ? AND expires_at <= ?", models.ReservationStatusActive, time.Now()).
+		Update("status", models.ReservationStatusReleased)
+
+	return result.RowsAffected, result.Error
+}
+
+// StartReservationSweeper releases expired reservations every interval until ctx is cancelled.
+func (s *InventoryService) StartReservationSweeper(ctx context.Context, interval time.Duration, logger *zerolog.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			released, err := s.ReleaseExpiredReservations()
+			if err != nil {
+				logger.Error().Err(err).Msg("failed to release expired stock reservations")
+				continue
+			}
+
+			if released > 0 {
+				logger.Info().Int64("released", released).Msg("released expired stock reservations")
+			}
+		}
+	}
+}
+
+func (s *InventoryService) recordMovement(tx *gorm.DB, productID uint, delta, stockAfter int, change *StockChange) error {
+	return tx.Create(&models.InventoryMovement{
+		ProductID:   productID,
+		Delta:       delta,
+		StockAfter:  stockAfter,
+		Reason:      change.Reason,
+		ReferenceID: change.ReferenceID,
+		ActorID:     change.ActorID,
+		Note:        change.Note,
+	}).Error
+}
+
+func (s *InventoryService) convertToMovementResponse(movement *models.InventoryMovement) dto.InventoryMovementResponse {
+	return dto.InventoryMovementResponse{
+		ID:          movement.ID,
+		ProductID:   movement.ProductID,
+		Delta:       movement.Delta,
+		StockAfter:  movement.StockAfter,
+		Reason:      string(movement.Reason),
+		ReferenceID: movement.ReferenceID,
+		ActorID:     movement.ActorID,
+		Note:        movement.Note,
+		CreatedAt:   movement.CreatedAt,
+	}
+}
+
+func (s *InventoryService) releaseUserReservations(tx *gorm.DB, userID uint) error {
+	return tx.Model(&models.StockReservation{}).
+		Where("user_id = ? AND status = ?", userID, models.ReservationStatusActive).
+		Update("status", models.ReservationStatusReleased).Error
+}
+
+func (s *InventoryService) activeReservations(db *gorm.DB, excludeUserID uint) *gorm.DB {
+	query := db.Model(&models.StockReservation{}).
+		Where("status = ? AND expires_at > ?", models.ReservationStatusActive, time.Now())
+
+	if excludeUserID != 0 {
+		query = query.Where("user_id <> ?", excludeUserID)
+	}
+
+	return query
+}
@@ -0,0 +1,62 @@
+package services
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/tomimandalaputra/e-commerce-go/internal/dto"
+	"github.com/tomimandalaputra/e-commerce-go/internal/models"
+	"github.com/tomimandalaputra/e-commerce-go/internal/providers"
+)
+
+// TestReservationRacesCheckout reserves the last unit of a product while
+// another customer checks it out, and checks that the two never both succeed.
+func TestReservationRacesCheckout(t *testing.T) {
+	db := openTestDB(t)
+
+	paymentService := NewPaymentService(providers.NewFakePaymentProvider(), "USD")
+	inventoryService := NewInventoryService(db, 15*time.Minute)
+	orderService := NewOrderService(db, paymentService, inventoryService, 30*time.Minute, false)
+
+	for range 20 {
+		product, userIDs := seedCheckout(t, db, 2, 1, 1)
+		reserver, buyer := userIDs[0], userIDs[1]
+
+		var (
+			wg                      sync.WaitGroup
+			reserveErr, checkoutErr error
+		)
+
+		start := make(chan struct{})
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			<-start
+			_, reserveErr = inventoryService.ReserveCart(reserver)
+		}()
+		go func() {
+			defer wg.Done()
+			<-start
+			_, checkoutErr = orderService.CreateOrder(buyer, &dto.CreateOrderRequest{PaymentToken: "tok_visa"})
+		}()
+
+		close(start)
+		wg.Wait()
+
+		for _, err := range []error{reserveErr, checkoutErr} {
+			if err != nil && !errors.Is(err, ErrInsufficientStock) {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+
+		if reserveErr == nil && checkoutErr == nil {
+			var final models.Product
+			if err := db.First(&final, product.ID).Error; err != nil {
+				t.Fatalf("failed to reload product: %v", err)
+			}
+			t.Fatalf("the last unit was both reserved and sold, stock is now %d", final.Stock)
+		}
+	}
+}
@@ -0,0 +1,317 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/tomimandalaputra/e-commerce-go/internal/config"
+	"github.com/tomimandalaputra/e-commerce-go/internal/dto"
+	"github.com/tomimandalaputra/e-commerce-go/internal/events"
+	"github.com/tomimandalaputra/e-commerce-go/internal/events/schema"
+	"github.com/tomimandalaputra/e-commerce-go/internal/models"
+	"github.com/tomimandalaputra/e-commerce-go/internal/utils"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	// ErrMFAAlreadyEnabled is returned when enrolling an account that already has two-factor authentication.
+	ErrMFAAlreadyEnabled = errors.New("two-factor authentication is already enabled")
+	// ErrMFANotSetUp is returned when enabling two-factor authentication before a secret was generated.
+	ErrMFANotSetUp = errors.New("two-factor authentication has not been set up")
+	// ErrMFANotEnabled is returned when an account without two-factor authentication tries to manage it.
+	ErrMFANotEnabled = errors.New("two-factor authentication is not enabled")
+	// ErrMFARequired is returned when an account may not turn off two-factor authentication.
+	ErrMFARequired = errors.New("two-factor authentication is required for this account")
+	// ErrInvalidMFACode is returned when a TOTP or recovery code is wrong or already used.
+	ErrInvalidMFACode = errors.New("invalid two-factor authentication code")
+)
+
+// MFAService manages TOTP two-factor authentication (RFC 6238) and the
+// recovery codes that stand in for a lost authenticator.
+type MFAService struct {
+	db          *gorm.DB
+	config      *config.Config
+	rbacService *RBACService
+}
+
+func NewMFAService(db *gorm.DB, cfg *config.Config, rbacService *RBACService) *MFAService {
+	return &MFAService{
+		db:          db,
+		config:      cfg,
+		rbacService: rbacService,
+	}
+}
+
+// IsEnabled reports whether a user has two-factor authentication turned on.
+func (s *MFAService) IsEnabled(userID uint) (bool, error) {
+	var count int64
+	err := s.db.Model(&models.User{}).
+		Where("id = ? AND totp_enabled_at IS NOT NULL", userID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// SetupTOTP generates a new TOTP secret for the user. It is not used for
+// login until EnableTOTP confirms the authenticator produces valid codes.
+func (s *MFAService) SetupTOTP(userID uint) (*dto.TOTPSetupResponse, error) {
+	var user models.User
+	if err := s.db.First(&user, userID).Error; err != nil {
+		return nil, err
+	}
+
+	if user.TOTPEnabledAt != nil {
+		return nil, ErrMFAAlreadyEnabled
+	}
+
+	secret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	encrypted, err := utils.Encrypt(s.config.Auth.MFA.EncryptionKey, secret)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.db.Model(&user).Updates(map[string]any{
+		"totp_secret":         encrypted,
+		"totp_last_used_step": nil,
+	}).Error; err != nil {
+		return nil, err
+	}
+
+	return &dto.TOTPSetupResponse{
+		Secret:          secret,
+		ProvisioningURI: utils.TOTPProvisioningURI(s.config.Auth.MFA.Issuer, user.Email, secret),
+	}, nil
+}
+
+// EnableTOTP turns on two-factor authentication once the user proves their
+// authenticator works, and returns a fresh set of recovery codes.
+func (s *MFAService) EnableTOTP(userID uint, req *dto.EnableTOTPRequest) (*dto.RecoveryCodesResponse, error) {
+	var codes []string
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var user models.User
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&user, userID).Error; err != nil {
+			return err
+		}
+
+		if user.TOTPEnabledAt != nil {
+			return ErrMFAAlreadyEnabled
+		}
+
+		if user.TOTPSecret == nil {
+			return ErrMFANotSetUp
+		}
+
+		valid, err := s.verifyTOTP(tx, &user, req.Code)
+		if err != nil {
+			return err
+		}
+
+		if !valid {
+			return ErrInvalidMFACode
+		}
+
+		if err := tx.Model(&user).Update("totp_enabled_at", time.Now()).Error; err != nil {
+			return err
+		}
+
+		codes, err = s.replaceRecoveryCodes(tx, user.ID)
+		if err != nil {
+			return err
+		}
+
+		return events.Enqueue(tx, schema.UserMFAEnabledV1{
+			UserID: user.ID,
+			Email:  user.Email,
+		}, "")
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.RecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// DisableTOTP turns off two-factor authentication. It needs both the password
+// and a TOTP or recovery code, and is refused to staff when the policy
+// requires two-factor authentication for them.
+func (s *MFAService) DisableTOTP(userID uint, req *dto.DisableTOTPRequest) error {
+	var user models.User
+	if err := s.db.First(&user, userID).Error; err != nil {
+		return err
+	}
+
+	if user.TOTPEnabledAt == nil {
+		return ErrMFANotEnabled
+	}
+
+	if s.config.Auth.MFA.RequireForAdmins {
+		staff, err := s.rbacService.IsStaff(user.ID)
+		if err != nil {
+			return err
+		}
+
+		if staff {
+			return ErrMFARequired
+		}
+	}
+
+	if !utils.CheckPassword(req.Password, user.Password) {
+		return ErrIncorrectPassword
+	}
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&user, userID).Error; err != nil {
+			return err
+		}
+
+		valid, err := s.verifyCode(tx, &user, req.Code)
+		if err != nil {
+			return err
+		}
+
+		if !valid {
+			return ErrInvalidMFACode
+		}
+
+		if err := tx.Model(&user).Updates(map[string]any{
+			"totp_secret":         nil,
+			"totp_enabled_at":     nil,
+			"totp_last_used_step": nil,
+		}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("user_id = ?", user.ID).Delete(&models.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+
+		return events.Enqueue(tx, schema.UserMFADisabledV1{
+			UserID: user.ID,
+			Email:  user.Email,
+		}, "")
+	})
+}
+
+// RegenerateRecoveryCodes replaces every recovery code of the user, used or not.
+func (s *MFAService) RegenerateRecoveryCodes(userID uint, req *dto.RegenerateRecoveryCodesRequest) (*dto.RecoveryCodesResponse, error) {
+	var codes []string
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var user models.User
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&user, userID).Error; err != nil {
+			return err
+		}
+
+		if user.TOTPEnabledAt == nil {
+			return ErrMFANotEnabled
+		}
+
+		valid, err := s.verifyTOTP(tx, &user, req.Code)
+		if err != nil {
+			return err
+		}
+
+		if !valid {
+			return ErrInvalidMFACode
+		}
+
+		codes, err = s.replaceRecoveryCodes(tx, user.ID)
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.RecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// verifyCode accepts either a TOTP code or an unused recovery code and
+// consumes it. The user row must be locked by tx.
+func (s *MFAService) verifyCode(tx *gorm.DB, user *models.User, code string) (bool, error) {
+	code = strings.TrimSpace(code)
+	if len(code) == 6 {
+		return s.verifyTOTP(tx, user, code)
+	}
+
+	return s.useRecoveryCode(tx, user, code)
+}
+
+// verifyTOTP checks a TOTP code and records its time step, so the same code
+// cannot be replayed. The user row must be locked by tx.
+func (s *MFAService) verifyTOTP(tx *gorm.DB, user *models.User, code string) (bool, error) {
+	if user.TOTPSecret == nil {
+		return false, nil
+	}
+
+	secret, err := utils.Decrypt(s.config.Auth.MFA.EncryptionKey, *user.TOTPSecret)
+	if err != nil {
+		return false, err
+	}
+
+	step, valid := utils.ValidateTOTP(secret, strings.TrimSpace(code), time.Now())
+	if !valid || (user.TOTPLastUsedStep != nil && step <= *user.TOTPLastUsedStep) {
+		return false, nil
+	}
+
+	if err := tx.Model(user).Update("totp_last_used_step", step).Error; err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (s *MFAService) useRecoveryCode(tx *gorm.DB, user *models.User, code string) (bool, error) {
+	hashes := tokenHashes(&s.config.Auth, normalizeRecoveryCode(code))
+
+	result := tx.Model(&models.RecoveryCode{}).
+		Where("user_id = ? AND code_hash IN ? AND used_at IS NULL", user.ID, hashes).
+		Update("used_at", time.Now())
+	return result.RowsAffected == 1, result.Error
+}
+
+// replaceRecoveryCodes deletes the recovery codes of a user and returns new
+// ones. Only their hashes are stored, so they can be shown this one time.
+func (s *MFAService) replaceRecoveryCodes(tx *gorm.DB, userID uint) ([]string, error) {
+	if err := tx.Where("user_id = ?", userID).Delete(&models.RecoveryCode{}).Error; err != nil {
+		return nil, err
+	}
+
+	codes := make([]string, 0, s.config.Auth.MFA.RecoveryCodeCount)
+	records := make([]models.RecoveryCode, 0, s.config.Auth.MFA.RecoveryCodeCount)
+	for range s.config.Auth.MFA.RecoveryCodeCount {
+		token, err := utils.GenerateToken(5)
+		if err != nil {
+			return nil, err
+		}
+
+		code := token[:5] + "-" + token[5:]
+		codes = append(codes, code)
+		records = append(records, models.RecoveryCode{
+			UserID:   userID,
+			CodeHash: utils.HashToken(s.config.Auth.TokenHashSecret, normalizeRecoveryCode(code)),
+		})
+	}
+
+	if len(records) == 0 {
+		return codes, nil
+	}
+
+	if err := tx.Create(&records).Error; err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+// normalizeRecoveryCode ignores case, spaces and dashes so codes can be typed loosely
+func normalizeRecoveryCode(code string) string {
+	return strings.NewReplacer("-", "", " ", "").Replace(strings.ToLower(code))
+}
@@ -0,0 +1,269 @@
+package services
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/tomimandalaputra/e-commerce-go/internal/events/schema"
+	"github.com/tomimandalaputra/e-commerce-go/internal/models"
+	"github.com/tomimandalaputra/e-commerce-go/internal/notifications"
+	"github.com/tomimandalaputra/e-commerce-go/internal/utils"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// NotificationService emails customers in response to domain events.
+type NotificationService struct {
+	db                 *gorm.DB
+	mailer             notifications.Mailer
+	templates          *notifications.Templates
+	frontendURL        string
+	tokenEncryptionKey string
+}
+
+func NewNotificationService(db *gorm.DB, mailer notifications.Mailer, templates *notifications.Templates, frontendURL, tokenEncryptionKey string) *NotificationService {
+	return &NotificationService{
+		db:                 db,
+		mailer:             mailer,
+		templates:          templates,
+		frontendURL:        strings.TrimSuffix(frontendURL, "/"),
+		tokenEncryptionKey: tokenEncryptionKey,
+	}
+}
+
+// HandleUserRegistered sends the welcome email.
+func (s *NotificationService) HandleUserRegistered(envelope *schema.Envelope) error {
+	var event schema.UserRegisteredV1
+	if err := envelope.Decode(&event); err != nil {
+		return err
+	}
+
+	return s.send(envelope, notifications.TemplateWelcome, event.Email, &event.UserID, notifications.AccountData{
+		FirstName: event.FirstName,
+		Email:     event.Email,
+	})
+}
+
+// HandleVerificationRequested sends the email verification link. The token is
+// decrypted from user_tokens and cleared once sent. Tokens that were used or
+// replaced in the meantime are not sent.
+func (s *NotificationService) HandleVerificationRequested(envelope *schema.Envelope) error {
+	if envelope.SchemaVersion == 1 {
+		var event schema.UserVerificationRequestedV1
+		if err := envelope.Decode(&event); err != nil {
+			return err
+		}
+
+		return s.sendVerifyEmail(envelope, event.Email, event.UserID, event.FirstName, event.Token, event.ExpiresAt)
+	}
+
+	var event schema.UserVerificationRequestedV2
+	if err := envelope.Decode(&event); err != nil {
+		return err
+	}
+
+	var token models.UserToken
+	if err := s.db.Where("id = ? AND used_at IS NULL AND expires_at > ? AND token_ciphertext IS NOT NULL", event.TokenID, time.Now()).
+		First(&token).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	raw, err := utils.Decrypt(s.tokenEncryptionKey, *token.TokenCiphertext)
+	if err != nil {
+		return err
+	}
+
+	if err := s.sendVerifyEmail(envelope, event.Email, event.UserID, event.FirstName, raw, event.ExpiresAt); err != nil {
+		return err
+	}
+
+	return s.db.Model(&token).Update("token_ciphertext", nil).Error
+}
+
+func (s *NotificationService) sendVerifyEmail(envelope *schema.Envelope, email string, userID uint, firstName, token string, expiresAt time.Time) error {
+	return s.send(envelope, notifications.TemplateVerifyEmail, email, &userID, notifications.VerifyEmailData{
+		FirstName: firstName,
+		VerifyURL: s.frontendURL + "/verify-email?token=" + url.QueryEscape(token),
+		ExpiresAt: expiresAt,
+	})
+}
+
+// HandlePasswordResetRequested sends the password reset link. Like
+// HandleVerificationRequested it decrypts the token from password_reset_tokens
+// and clears it once sent.
+func (s *NotificationService) HandlePasswordResetRequested(envelope *schema.Envelope) error {
+	if envelope.SchemaVersion == 1 {
+		var event schema.PasswordResetRequestedV1
+		if err := envelope.Decode(&event); err != nil {
+			return err
+		}
+
+		return s.sendPasswordReset(envelope, event.Email, event.UserID, event.FirstName, event.Token, event.ExpiresAt)
+	}
+
+	var event schema.PasswordResetRequestedV2
+	if err := envelope.Decode(&event); err != nil {
+		return err
+	}
+
+	var token models.PasswordResetToken
+	if err := s.db.Where("id = ? AND used_at IS NULL AND expires_at > ? AND token_ciphertext IS NOT NULL", event.TokenID, time.Now()).
+		First(&token).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	raw, err := utils.Decrypt(s.tokenEncryptionKey, *token.TokenCiphertext)
+	if err != nil {
+		return err
+	}
+
+	if err := s.sendPasswordReset(envelope, event.Email, event.UserID, event.FirstName, raw, event.ExpiresAt); err != nil {
+		return err
+	}
+
+	return s.db.Model(&token).Update("token_ciphertext", nil).Error
+}
+
+func (s *NotificationService) sendPasswordReset(envelope *schema.Envelope, email string, userID uint, firstName, token string, expiresAt time.Time) error {
+	return s.send(envelope, notifications.TemplatePasswordReset, email, &userID, notifications.PasswordResetData{
+		FirstName: firstName,
+		ResetURL:  s.frontendURL + "/reset-password?token=" + url.QueryEscape(token),
+		ExpiresAt: expiresAt,
+	})
+}
+
+// HandlePasswordChanged tells the user their password was reset or changed,
+// in case it was not them.
+func (s *NotificationService) HandlePasswordChanged(envelope *schema.Envelope) error {
+	var userID uint
+	switch envelope.EventType {
+	case schema.UserPasswordReset:
+		var event schema.UserPasswordResetV1
+		if err := envelope.Decode(&event); err != nil {
+			return err
+		}
+		userID = event.UserID
+	case schema.UserPasswordChanged:
+		var event schema.UserPasswordChangedV1
+		if err := envelope.Decode(&event); err != nil {
+			return err
+		}
+		userID = event.UserID
+	default:
+		return nil
+	}
+
+	var user models.User
+	if err := s.db.First(&user, userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	return s.send(envelope, notifications.TemplatePasswordChanged, user.Email, &user.ID, notifications.AccountData{
+		FirstName: user.FirstName,
+		Email:     user.Email,
+	})
+}
+
+// HandleOrderCreated sends the order confirmation.
+func (s *NotificationService) HandleOrderCreated(envelope *schema.Envelope) error {
+	var event schema.OrderCreatedV1
+	if err := envelope.Decode(&event); err != nil {
+		return err
+	}
+
+	return s.sendOrderEmail(envelope, notifications.TemplateOrderConfirmation, event.OrderID, "")
+}
+
+// HandleOrderStatusChanged tells the customer their order shipped or was
+// cancelled. Other transitions are not emailed.
+func (s *NotificationService) HandleOrderStatusChanged(envelope *schema.Envelope) error {
+	var event schema.OrderStatusChangedV1
+	if err := envelope.Decode(&event); err != nil {
+		return err
+	}
+
+	switch models.OrderStatus(event.ToStatus) {
+	case models.OrderStatusShipped:
+		return s.sendOrderEmail(envelope, notifications.TemplateOrderShipped, event.OrderID, "")
+	case models.OrderStatusCancelled:
+		return s.sendOrderEmail(envelope, notifications.TemplateOrderCancelled, event.OrderID, event.Reason)
+	default:
+		return nil
+	}
+}
+
+func (s *NotificationService) sendOrderEmail(envelope *schema.Envelope, template string, orderID uint, reason string) error {
+	var order models.Order
+	if err := s.db.Unscoped().Preload("User").Preload("OrderItems.Product", func(db *gorm.DB) *gorm.DB {
+		return db.Unscoped()
+	}).First(&order, orderID).Error; err != nil {
+		// Nothing to tell anyone about an order that no longer exists
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	if order.User.Email == "" {
+		return nil
+	}
+
+	items := make([]notifications.OrderLine, len(order.OrderItems))
+	for i := range order.OrderItems {
+		items[i] = notifications.OrderLine{
+			Name:     order.OrderItems[i].Product.Name,
+			Quantity: order.OrderItems[i].Quantity,
+			Price:    order.OrderItems[i].Price,
+		}
+	}
+
+	return s.send(envelope, template, order.User.Email, &order.UserID, notifications.OrderData{
+		FirstName:   order.User.FirstName,
+		OrderID:     order.ID,
+		Items:       items,
+		TotalAmount: order.TotalAmount,
+		Reason:      reason,
+	})
+}
+
+// send renders and mails a template once per event. Events are delivered at
+// least once, so emails already sent for the event are skipped.
+func (s *NotificationService) send(envelope *schema.Envelope, template, to string, userID *uint, data any) error {
+	var sent int64
+	if err := s.db.Model(&models.EmailNotification{}).
+		Where("event_id = ? AND template = ?", envelope.EventID, template).
+		Count(&sent).Error; err != nil {
+		return err
+	}
+
+	if sent > 0 {
+		return nil
+	}
+
+	msg, err := s.templates.Render(template, to, data)
+	if err != nil {
+		return err
+	}
+
+	if err := s.mailer.Send(msg); err != nil {
+		return err
+	}
+
+	return s.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&models.EmailNotification{
+		EventID:   envelope.EventID,
+		Template:  template,
+		Recipient: to,
+		UserID:    userID,
+	}).Error
+}
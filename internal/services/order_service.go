@@ -1,53 +1,173 @@
 package services
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"sort"
+	"strconv"
+	"time"
 
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
 	"github.com/tomimandalaputra/e-commerce-go/internal/dto"
+	"github.com/tomimandalaputra/e-commerce-go/internal/events"
+	"github.com/tomimandalaputra/e-commerce-go/internal/events/schema"
+	"github.com/tomimandalaputra/e-commerce-go/internal/interfaces"
 	"github.com/tomimandalaputra/e-commerce-go/internal/models"
 	"github.com/tomimandalaputra/e-commerce-go/internal/utils"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 const (
 	defaultDateFormat = "2006-01-02T15:04:05Z"
+
+	// settlementRetryDelay is how long a capture, void or refund that failed
+	// or was interrupted waits before SettlePayments tries it again
+	settlementRetryDelay = time.Minute
+)
+
+// pendingSettlements are the payment statuses waiting for the provider
+var pendingSettlements = []models.PaymentStatus{
+	models.PaymentStatusCapturePending,
+	models.PaymentStatusVoidPending,
+	models.PaymentStatusRefundPending,
+}
+
+var (
+	// ErrOrderNotFound is returned when an order does not exist or is not visible to the caller.
+	ErrOrderNotFound = errors.New("order not found")
+	// ErrInvalidOrderTransition is returned when an order status change is not allowed.
+	ErrInvalidOrderTransition = errors.New("invalid order status transition")
+	// ErrEmailNotVerified is returned when an unverified account tries to check out.
+	ErrEmailNotVerified = errors.New("email address is not verified")
+	// ErrCartEmpty is returned when the user checks out without any items in the cart.
+	ErrCartEmpty = errors.New("cart is empty")
+	// ErrCheckoutInProgress is returned when the user checks out while the payment of another checkout is being authorized.
+	ErrCheckoutInProgress = errors.New("another checkout is in progress")
 )
 
 type OrderService struct {
-	db *gorm.DB
+	db                   *gorm.DB
+	paymentService       *PaymentService
+	inventoryService     *InventoryService
+	authorizationWindow  time.Duration
+	requireVerifiedEmail bool
 }
 
-// NewOrderService creates the order service type
-func NewOrderService(db *gorm.DB) *OrderService {
-	return &OrderService{db: db}
+// NewOrderService creates the order service type. Checkouts still awaiting
+// their payment after authorizationWindow are cancelled by
+// CancelAbandonedCheckouts. When requireVerifiedEmail is set, only accounts
+// with a verified email address can place orders.
+func NewOrderService(db *gorm.DB, paymentService *PaymentService, inventoryService *InventoryService, authorizationWindow time.Duration, requireVerifiedEmail bool) *OrderService {
+	return &OrderService{
+		db:                   db,
+		paymentService:       paymentService,
+		inventoryService:     inventoryService,
+		authorizationWindow:  authorizationWindow,
+		requireVerifiedEmail: requireVerifiedEmail,
+	}
 }
 
-func (s *OrderService) CreateOrder(userID uint) (*dto.OrderResponse, error) {
-	var orderResponse *dto.OrderResponse
+// CreateOrder checks out the user's cart. The order is placed and its stock
+// taken in one transaction, its payment is then authorized without holding a
+// transaction open, and a second transaction completes or cancels the order
+// depending on the outcome. Declined and failed payments stay recorded on the
+// cancelled order, and the cart is kept so the customer can try again.
+func (s *OrderService) CreateOrder(userID uint, req *dto.CreateOrderRequest) (*dto.OrderResponse, error) {
+	if s.requireVerifiedEmail {
+		var user models.User
+		if err := s.db.Select("id", "email_verified_at").First(&user, userID).Error; err != nil {
+			return nil, err
+		}
 
-	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if user.EmailVerifiedAt == nil {
+			return nil, ErrEmailNotVerified
+		}
+	}
+
+	checkout, err := s.placeOrder(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.paymentService.AuthorizePayment(checkout.payment, req.PaymentToken); err != nil {
+		if cancelErr := s.cancelUnpaidOrder(userID, checkout, "payment not authorized: "+checkout.payment.FailureReason); cancelErr != nil {
+			return nil, errors.Join(err, cancelErr)
+		}
+		return nil, err
+	}
+
+	orderResponse, err := s.completeOrder(userID, checkout)
+	if err != nil {
+		// Nothing would ever capture the authorization of an order that was not
+		// completed. The order is cancelled even when the void fails; the payment
+		// is then left void_pending for SettlePayments.
+		voidErr := s.paymentService.VoidPayment(checkout.payment)
+		cancelErr := s.cancelUnpaidOrder(userID, checkout, "order could not be completed")
+		return nil, errors.Join(err, voidErr, cancelErr)
+	}
 
+	return orderResponse, nil
+}
+
+// placedOrder is an order placed by CreateOrder whose payment is not authorized yet.
+type placedOrder struct {
+	order   *models.Order
+	payment *models.Payment
+	cartID  uint
+	// stockAlerts are published once the order is completed
+	stockAlerts []schema.Event
+}
+
+// placeOrder creates a pending_payment order from the user's cart with a
+// pending payment, and takes its items out of stock.
+func (s *OrderService) placeOrder(userID uint) (*placedOrder, error) {
+	var checkout *placedOrder
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		// Locking the cart serializes checkouts of the same user, so the cart
+		// cannot be ordered twice while a payment is being authorized
 		var cart models.Cart
-		if err := tx.Preload("CartItems.Product").Where("user_id = ?", userID).First(&cart).Error; err != nil {
-			return errors.New("cart not found")
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("user_id = ?", userID).First(&cart).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrCartEmpty
+			}
+			return err
+		}
+
+		var inProgress int64
+		if err := tx.Model(&models.Order{}).
+			Where("user_id = ? AND status = ?", userID, models.OrderStatusPendingPayment).
+			Count(&inProgress).Error; err != nil {
+			return err
+		}
+
+		if inProgress > 0 {
+			return ErrCheckoutInProgress
+		}
+
+		if err := tx.Preload("CartItems.Product").First(&cart, cart.ID).Error; err != nil {
+			return err
 		}
 
 		if len(cart.CartItems) == 0 {
-			return errors.New("cart is empty")
+			return ErrCartEmpty
 		}
 
-		// Calculate total and validate stock
+		// Lock product rows in a consistent order so concurrent checkouts cannot deadlock
+		sort.Slice(cart.CartItems, func(i, j int) bool {
+			return cart.CartItems[i].ProductID < cart.CartItems[j].ProductID
+		})
+
+		// Calculate total
 		var totalAmount float64
 		var orderItems []models.OrderItem
 
 		for i := range cart.CartItems {
 			cartItem := &cart.CartItems[i]
 
-			if cartItem.Product.Stock < cartItem.Quantity {
-				return fmt.Errorf("insufficient stock for product: %s", cartItem.Product.Name)
-			}
-
 			itemTotal := float64(cartItem.Quantity) * cartItem.Product.Price
 			totalAmount += itemTotal
 
@@ -56,19 +176,12 @@ func (s *OrderService) CreateOrder(userID uint) (*dto.OrderResponse, error) {
 				Quantity:  cartItem.Quantity,
 				Price:     cartItem.Product.Price,
 			})
-
-			// Update product stock
-			cartItem.Product.Stock -= cartItem.Quantity
-			if err := tx.Save(&cartItem.Product).Error; err != nil {
-				return err
-			}
-
 		}
 
 		// Create order
 		order := models.Order{
 			UserID:      userID,
-			Status:      models.OrderStatusPending,
+			Status:      models.OrderStatusPendingPayment,
 			TotalAmount: totalAmount,
 			OrderItems:  orderItems,
 		}
@@ -77,11 +190,107 @@ func (s *OrderService) CreateOrder(userID uint) (*dto.OrderResponse, error) {
 			return err
 		}
 
+		checkout = &placedOrder{order: &order, cartID: cart.ID}
+
+		// Decrement stock atomically; the row is only updated while enough
+		// stock is left that is not reserved by other customers
+		for i := range cart.CartItems {
+			cartItem := &cart.CartItems[i]
+
+			product, err := s.inventoryService.DecrementStock(tx, cartItem.ProductID, userID, cartItem.Quantity, &StockChange{
+				Reason:      models.InventoryReasonOrder,
+				ReferenceID: strconv.FormatUint(uint64(order.ID), 10),
+				ActorID:     &userID,
+			})
+			if err != nil {
+				if errors.Is(err, ErrInsufficientStock) {
+					return fmt.Errorf("%w for product: %s", ErrInsufficientStock, cartItem.Product.Name)
+				}
+				return err
+			}
+
+			if alert := s.inventoryService.StockAlert(product, cartItem.Quantity, order.ID); alert != nil {
+				checkout.stockAlerts = append(checkout.stockAlerts, alert)
+			}
+		}
+
+		if err := s.recordStatusChange(tx, order.ID, nil, order.Status, &userID, "order placed"); err != nil {
+			return err
+		}
+
+		if err := s.inventoryService.ConsumeReservations(tx, userID, order.ID); err != nil {
+			return err
+		}
+
+		payment, err := s.paymentService.CreatePayment(tx, &order)
+		if err != nil {
+			return err
+		}
+
+		checkout.payment = payment
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return checkout, nil
+}
+
+// completeOrder records the authorized payment of a placed order, moves it to
+// pending, clears the cart and announces the order.
+func (s *OrderService) completeOrder(userID uint, checkout *placedOrder) (*dto.OrderResponse, error) {
+	var orderResponse *dto.OrderResponse
+
+	// Every event raised by this checkout shares one correlation ID
+	correlationID := uuid.NewString()
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var order models.Order
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&order, checkout.order.ID).Error; err != nil {
+			return err
+		}
+
+		if err := s.paymentService.RecordPayment(tx, checkout.payment); err != nil {
+			return err
+		}
+
+		if err := s.transitionOrder(tx, &order, models.OrderStatusPending, &userID, "payment authorized"); err != nil {
+			return err
+		}
+
 		// Clear cart
-		if err := tx.Where("cart_id = ?", cart.ID).Delete(&models.CartItem{}).Error; err != nil {
+		if err := tx.Where("cart_id = ?", checkout.cartID).Delete(&models.CartItem{}).Error; err != nil {
+			return err
+		}
+
+		orderItems := checkout.order.OrderItems
+		items := make([]schema.OrderItemV1, len(orderItems))
+		for i := range orderItems {
+			items[i] = schema.OrderItemV1{
+				ProductID: orderItems[i].ProductID,
+				Quantity:  orderItems[i].Quantity,
+				Price:     orderItems[i].Price,
+			}
+		}
+
+		if err := events.Enqueue(tx, schema.OrderCreatedV1{
+			OrderID:     order.ID,
+			UserID:      order.UserID,
+			Status:      string(order.Status),
+			TotalAmount: order.TotalAmount,
+			Items:       items,
+		}, correlationID); err != nil {
 			return err
 		}
 
+		for _, alert := range checkout.stockAlerts {
+			if err := events.Enqueue(tx, alert, correlationID); err != nil {
+				return err
+			}
+		}
+
 		response, err := s.getOrderResponse(tx, order.ID)
 		if err != nil {
 			return err
@@ -96,7 +305,158 @@ func (s *OrderService) CreateOrder(userID uint) (*dto.OrderResponse, error) {
 	}
 
 	return orderResponse, nil
+}
+
+// cancelUnpaidOrder stores the final state of the payment of a placed order
+// and cancels the order, putting its items back into stock.
+func (s *OrderService) cancelUnpaidOrder(userID uint, checkout *placedOrder, reason string) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		var order models.Order
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&order, checkout.order.ID).Error; err != nil {
+			return err
+		}
 
+		if err := s.paymentService.RecordPayment(tx, checkout.payment); err != nil {
+			return err
+		}
+
+		return s.transitionOrder(tx, &order, models.OrderStatusCancelled, &userID, reason)
+	})
+}
+
+// CancelAbandonedCheckouts cancels orders still awaiting their payment after
+// the authorization window, left behind when a checkout was interrupted
+// between placing the order and recording its authorization. Any
+// authorization has lapsed by then, so their payment is marked failed.
+func (s *OrderService) CancelAbandonedCheckouts() (int64, error) {
+	var orderIDs []uint
+	if err := s.db.Model(&models.Order{}).
+		Where("status = ? AND created_at < ?", models.OrderStatusPendingPayment, time.Now().Add(-s.authorizationWindow)).
+		Pluck("id", &orderIDs).Error; err != nil {
+		return 0, err
+	}
+
+	var cancelled int64
+	for _, orderID := range orderIDs {
+		err := s.db.Transaction(func(tx *gorm.DB) error {
+			var order models.Order
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&order, orderID).Error; err != nil {
+				return err
+			}
+
+			// The checkout may have finished since the orders were listed
+			if order.Status != models.OrderStatusPendingPayment {
+				return nil
+			}
+
+			if err := tx.Model(&models.Payment{}).
+				Where("order_id = ? AND status = ?", order.ID, models.PaymentStatusPending).
+				Updates(map[string]any{
+					"status":         models.PaymentStatusFailed,
+					"failure_reason": "checkout abandoned",
+				}).Error; err != nil {
+				return err
+			}
+
+			if err := s.transitionOrder(tx, &order, models.OrderStatusCancelled, nil, "checkout abandoned"); err != nil {
+				return err
+			}
+
+			cancelled++
+			return nil
+		})
+		if err != nil {
+			return cancelled, err
+		}
+	}
+
+	return cancelled, nil
+}
+
+// SettlePayments retries the captures, voids and refunds that failed or were
+// interrupted, so no order is left with money held or not collected.
+func (s *OrderService) SettlePayments() (int64, error) {
+	var payments []models.Payment
+	if err := s.db.Where("status IN ? AND updated_at < ?", pendingSettlements, time.Now().Add(-settlementRetryDelay)).
+		Find(&payments).Error; err != nil {
+		return 0, err
+	}
+
+	return s.settlePayments(payments)
+}
+
+// settleOrderPayments carries out the capture, void or refund that a status
+// change of the order left pending, once its transaction has committed.
+// A failure stays on the payment for SettlePayments to retry.
+func (s *OrderService) settleOrderPayments(orderID uint) {
+	var payments []models.Payment
+	if err := s.db.Where("order_id = ? AND status IN ?", orderID, pendingSettlements).Find(&payments).Error; err != nil {
+		return
+	}
+
+	_, _ = s.settlePayments(payments)
+}
+
+func (s *OrderService) settlePayments(payments []models.Payment) (int64, error) {
+	var settled int64
+	var errs []error
+	for i := range payments {
+		payment := &payments[i]
+		pending := payment.Status
+
+		// Claim the payment by touching it, so a concurrent sweep does not
+		// ask the provider a second time
+		claim := s.db.Model(&models.Payment{}).
+			Where("id = ? AND status = ? AND updated_at = ?", payment.ID, pending, payment.UpdatedAt).
+			Update("updated_at", time.Now())
+		if claim.Error != nil {
+			return settled, claim.Error
+		}
+		if claim.RowsAffected == 0 {
+			continue
+		}
+
+		settleErr := s.paymentService.Settle(payment)
+		if err := s.paymentService.RecordSettlement(s.db, payment, pending); err != nil {
+			return settled, err
+		}
+
+		if settleErr != nil {
+			errs = append(errs, settleErr)
+			continue
+		}
+		settled++
+	}
+
+	return settled, errors.Join(errs...)
+}
+
+// StartCheckoutSweeper cancels abandoned checkouts and retries failed
+// captures, voids and refunds every interval until ctx is cancelled.
+func (s *OrderService) StartCheckoutSweeper(ctx context.Context, interval time.Duration, logger *zerolog.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			cancelled, err := s.CancelAbandonedCheckouts()
+			if err != nil {
+				logger.Error().Err(err).Msg("failed to cancel abandoned checkouts")
+			} else if cancelled > 0 {
+				logger.Info().Int64("cancelled", cancelled).Msg("cancelled abandoned checkouts")
+			}
+
+			settled, err := s.SettlePayments()
+			if err != nil {
+				logger.Error().Err(err).Msg("failed to settle payments")
+			} else if settled > 0 {
+				logger.Info().Int64("settled", settled).Msg("settled payments")
+			}
+		}
+	}
 }
 
 func (s *OrderService) GetOrders(userID uint, page, limit int) ([]dto.OrderResponse, *utils.PaginationMeta, error) {
@@ -120,6 +480,8 @@ func (s *OrderService) GetOrders(userID uint, page, limit int) ([]dto.OrderRespo
 
 	if err := s.db.Preload("OrderItems.Product.Category").
 		Preload("OrderItems.Product.Images").
+		Preload("StatusHistory", orderStatusHistoryOrder).
+		Preload("Payment").
 		Where("user_id = ?", userID).
 		Order("created_at DESC").
 		Offset(offset).Limit(limit).
@@ -127,10 +489,15 @@ func (s *OrderService) GetOrders(userID uint, page, limit int) ([]dto.OrderRespo
 		return nil, nil, err
 	}
 
+	reserved, err := s.reservedQuantities(s.db, orders...)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	response := make([]dto.OrderResponse, len(orders))
 	for i := range orders {
 		order := &orders[i]
-		response[i] = s.convertToOrderResponse(order)
+		response[i] = s.convertToOrderResponse(order, reserved)
 	}
 
 	totalPages := int((total + int64(limit) - 1) / int64(limit))
@@ -148,30 +515,257 @@ func (s *OrderService) GetOrder(userID, orderID uint) (*dto.OrderResponse, error
 	var order models.Order
 	if err := s.db.Preload("OrderItems.Product.Category").
 		Preload("OrderItems.Product.Images").
+		Preload("StatusHistory", orderStatusHistoryOrder).
+		Preload("Payment").
 		Where("id = ? AND user_id = ?", orderID, userID).
 		First(&order).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrOrderNotFound
+		}
 		return nil, err
 	}
 
-	response := s.convertToOrderResponse(&order)
+	reserved, err := s.reservedQuantities(s.db, order)
+	if err != nil {
+		return nil, err
+	}
+
+	response := s.convertToOrderResponse(&order, reserved)
 
 	return &response, nil
 }
 
+// UpdateOrderStatus moves an order to a new status on behalf of an admin.
+func (s *OrderService) UpdateOrderStatus(actorID, orderID uint, req *dto.UpdateOrderStatusRequest) (*dto.OrderResponse, error) {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var order models.Order
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&order, orderID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrOrderNotFound
+			}
+			return err
+		}
+
+		// Only a successful authorization may move an order out of pending_payment
+		to := models.OrderStatus(req.Status)
+		if order.Status == models.OrderStatusPendingPayment && to != models.OrderStatusCancelled {
+			return fmt.Errorf("%w: payment of the order is still being authorized", ErrInvalidOrderTransition)
+		}
+
+		return s.transitionOrder(tx, &order, to, &actorID, req.Reason)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	s.settleOrderPayments(orderID)
+
+	return s.getOrderResponse(s.db, orderID)
+}
+
+// CancelOrder cancels one of the user's own orders while it is still pending or confirmed.
+func (s *OrderService) CancelOrder(userID, orderID uint) (*dto.OrderResponse, error) {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var order models.Order
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND user_id = ?", orderID, userID).
+			First(&order).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrOrderNotFound
+			}
+			return err
+		}
+
+		if !customerCancellableStatuses[order.Status] {
+			return fmt.Errorf("%w: order can no longer be cancelled once %s", ErrInvalidOrderTransition, order.Status)
+		}
+
+		return s.transitionOrder(tx, &order, models.OrderStatusCancelled, &userID, "cancelled by customer")
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	s.settleOrderPayments(orderID)
+
+	return s.getOrderResponse(s.db, orderID)
+}
+
+// ApplyPaymentEvent moves a pending order forward from an asynchronous payment
+// notification: a successful payment confirms the order and a failed one
+// cancels it. Events for orders that already left pending are ignored.
+func (s *OrderService) ApplyPaymentEvent(tx *gorm.DB, event *interfaces.PaymentWebhookEvent) error {
+	payment, err := s.paymentService.FindByProviderPaymentID(tx, event.ProviderPaymentID)
+	if err != nil {
+		return err
+	}
+
+	var order models.Order
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&order, payment.OrderID).Error; err != nil {
+		return err
+	}
+
+	if order.Status != models.OrderStatusPending {
+		return nil
+	}
+
+	switch event.Type {
+	case interfaces.PaymentEventSucceeded:
+		if err := s.paymentService.MarkCaptured(tx, payment); err != nil {
+			return err
+		}
+		return s.transitionOrder(tx, &order, models.OrderStatusConfirmed, nil, "payment succeeded")
+	case interfaces.PaymentEventFailed:
+		if err := s.paymentService.MarkFailed(tx, payment, event.FailureReason); err != nil {
+			return err
+		}
+		return s.transitionOrder(tx, &order, models.OrderStatusCancelled, nil, "payment failed: "+event.FailureReason)
+	default:
+		return fmt.Errorf("unsupported payment event type: %s", event.Type)
+	}
+}
+
+// GetOrderHistory returns the status timeline of an order. Customers can only
+// read their own orders; admins can read any order.
+func (s *OrderService) GetOrderHistory(userID, orderID uint, isAdmin bool) ([]dto.OrderStatusHistoryResponse, error) {
+	query := s.db.Model(&models.Order{}).Where("id = ?", orderID)
+	if !isAdmin {
+		query = query.Where("user_id = ?", userID)
+	}
+
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		return nil, err
+	}
+
+	if count == 0 {
+		return nil, ErrOrderNotFound
+	}
+
+	var history []models.OrderStatusHistory
+	if err := s.db.Where("order_id = ?", orderID).
+		Order("created_at ASC, id ASC").
+		Find(&history).Error; err != nil {
+		return nil, err
+	}
+
+	return s.convertToStatusHistoryResponse(history), nil
+}
+
+// transitionOrder validates and applies a status change to a locked order row
+// and records it in the order's status history.
+// Confirming an order marks its payment to be captured; cancelling it marks the
+// payment to be released and puts its items back into stock. The provider is
+// only called by settleOrderPayments after the transaction has committed, so
+// the order row is not kept locked while it responds.
+func (s *OrderService) transitionOrder(tx *gorm.DB, order *models.Order, to models.OrderStatus, actorID *uint, reason string) error {
+	from := order.Status
+	if err := validateOrderTransition(from, to); err != nil {
+		return err
+	}
+
+	switch to {
+	case models.OrderStatusConfirmed:
+		if err := s.paymentService.RequestCapture(tx, order.ID); err != nil {
+			return err
+		}
+	case models.OrderStatusCancelled:
+		if err := s.paymentService.RequestRelease(tx, order.ID); err != nil {
+			return err
+		}
+		if err := s.restoreStock(tx, order.ID, actorID); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Model(order).Update("status", to).Error; err != nil {
+		return err
+	}
+	order.Status = to
+
+	return s.recordStatusChange(tx, order.ID, &from, to, actorID, reason)
+}
+
+func (s *OrderService) recordStatusChange(tx *gorm.DB, orderID uint, from *models.OrderStatus, to models.OrderStatus, actorID *uint, reason string) error {
+	if err := tx.Create(&models.OrderStatusHistory{
+		OrderID:    orderID,
+		FromStatus: from,
+		ToStatus:   to,
+		ActorID:    actorID,
+		Reason:     reason,
+	}).Error; err != nil {
+		return err
+	}
+
+	// Orders are announced by ORDER_CREATED once their payment is authorized;
+	// nothing is published about them before that
+	if from == nil || *from == models.OrderStatusPendingPayment {
+		return nil
+	}
+
+	return events.Enqueue(tx, schema.OrderStatusChangedV1{
+		OrderID:    orderID,
+		FromStatus: string(*from),
+		ToStatus:   string(to),
+		ActorID:    actorID,
+		Reason:     reason,
+	}, "")
+}
+
+func (s *OrderService) restoreStock(tx *gorm.DB, orderID uint, actorID *uint) error {
+	var items []models.OrderItem
+	if err := tx.Where("order_id = ?", orderID).Find(&items).Error; err != nil {
+		return err
+	}
+
+	for i := range items {
+		if err := s.inventoryService.AdjustStock(tx, items[i].ProductID, items[i].Quantity, &StockChange{
+			Reason:      models.InventoryReasonCancellation,
+			ReferenceID: strconv.FormatUint(uint64(orderID), 10),
+			ActorID:     actorID,
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (s *OrderService) getOrderResponse(tx *gorm.DB, orderID uint) (*dto.OrderResponse, error) {
 	var order models.Order
 	if err := tx.Preload("OrderItems.Product.Category").
 		Preload("OrderItems.Product.Images").
+		Preload("StatusHistory", orderStatusHistoryOrder).
+		Preload("Payment").
 		First(&order, orderID).Error; err != nil {
 		return nil, err
 	}
 
-	response := s.convertToOrderResponse(&order)
+	reserved, err := s.reservedQuantities(tx, order)
+	if err != nil {
+		return nil, err
+	}
+
+	response := s.convertToOrderResponse(&order, reserved)
 
 	return &response, nil
 }
 
-func (s *OrderService) convertToOrderResponse(order *models.Order) dto.OrderResponse {
+// reservedQuantities looks up the reserved stock of every product in the orders.
+func (s *OrderService) reservedQuantities(db *gorm.DB, orders ...models.Order) (map[uint]int, error) {
+	var productIDs []uint
+	for i := range orders {
+		for j := range orders[i].OrderItems {
+			productIDs = append(productIDs, orders[i].OrderItems[j].ProductID)
+		}
+	}
+
+	return s.inventoryService.ReservedQuantities(db, productIDs, 0)
+}
+
+func (s *OrderService) convertToOrderResponse(order *models.Order, reserved map[uint]int) dto.OrderResponse {
 	orderItems := make([]dto.OrderItemResponse, len(order.OrderItems))
 	for i := range order.OrderItems {
 		item := &order.OrderItems[i]
@@ -197,6 +791,7 @@ func (s *OrderService) convertToOrderResponse(order *models.Order) dto.OrderResp
 				Description: item.Product.Description,
 				Price:       item.Product.Price,
 				Stock:       item.Product.Stock,
+				Available:   max(item.Product.Stock-reserved[item.ProductID], 0),
 				SKU:         item.Product.SKU,
 				IsActive:    item.Product.IsActive,
 				Category: dto.CategoryResponse{
@@ -213,11 +808,55 @@ func (s *OrderService) convertToOrderResponse(order *models.Order) dto.OrderResp
 	}
 
 	return dto.OrderResponse{
-		ID:          order.ID,
-		UserID:      order.UserID,
-		Status:      string(order.Status),
-		TotalAmount: order.TotalAmount,
-		OrderItems:  orderItems,
-		CreatedAt:   order.CreatedAt.Format(defaultDateFormat),
+		ID:            order.ID,
+		UserID:        order.UserID,
+		Status:        string(order.Status),
+		TotalAmount:   order.TotalAmount,
+		OrderItems:    orderItems,
+		StatusHistory: s.convertToStatusHistoryResponse(order.StatusHistory),
+		Payment:       s.convertToPaymentResponse(order.Payment),
+		CreatedAt:     order.CreatedAt.Format(defaultDateFormat),
+	}
+}
+
+func (s *OrderService) convertToPaymentResponse(payment *models.Payment) *dto.PaymentResponse {
+	if payment == nil {
+		return nil
+	}
+
+	return &dto.PaymentResponse{
+		ID:            payment.ID,
+		Provider:      payment.Provider,
+		Amount:        payment.Amount,
+		Currency:      payment.Currency,
+		Status:        string(payment.Status),
+		FailureReason: payment.FailureReason,
+	}
+}
+
+func (s *OrderService) convertToStatusHistoryResponse(history []models.OrderStatusHistory) []dto.OrderStatusHistoryResponse {
+	response := make([]dto.OrderStatusHistoryResponse, len(history))
+	for i := range history {
+		entry := &history[i]
+
+		var from string
+		if entry.FromStatus != nil {
+			from = string(*entry.FromStatus)
+		}
+
+		response[i] = dto.OrderStatusHistoryResponse{
+			ID:         entry.ID,
+			FromStatus: from,
+			ToStatus:   string(entry.ToStatus),
+			ActorID:    entry.ActorID,
+			Reason:     entry.Reason,
+			CreatedAt:  entry.CreatedAt.Format(defaultDateFormat),
+		}
 	}
+
+	return response
+}
+
+func orderStatusHistoryOrder(db *gorm.DB) *gorm.DB {
+	return db.Order("created_at ASC, id ASC")
 }
@@ -0,0 +1,305 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/tomimandalaputra/e-commerce-go/internal/dto"
+	"github.com/tomimandalaputra/e-commerce-go/internal/events/schema"
+	"github.com/tomimandalaputra/e-commerce-go/internal/models"
+	"github.com/tomimandalaputra/e-commerce-go/internal/providers"
+)
+
+// testDatabaseDSNEnv names the Postgres database, with every migration
+// applied, that tests needing a real database run against.
+const testDatabaseDSNEnv = "TEST_DATABASE_DSN"
+
+func openTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	dsn := os.Getenv(testDatabaseDSNEnv)
+	if dsn == "" {
+		t.Skipf("%s is not set", testDatabaseDSNEnv)
+	}
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("failed to connect to test database: %v", err)
+	}
+
+	return db
+}
+
+// TestCreateOrderConcurrentCheckouts fires parallel checkouts for a single
+// product and checks that it is never oversold.
+func TestCreateOrderConcurrentCheckouts(t *testing.T) {
+	db := openTestDB(t)
+
+	const (
+		buyers   = 50
+		stock    = 10
+		quantity = 1
+	)
+
+	product, userIDs := seedCheckout(t, db, buyers, stock, quantity)
+
+	paymentService := NewPaymentService(providers.NewFakePaymentProvider(), "USD")
+	inventoryService := NewInventoryService(db, 15*time.Minute)
+	orderService := NewOrderService(db, paymentService, inventoryService, 30*time.Minute, false)
+
+	var (
+		wg         sync.WaitGroup
+		mu         sync.Mutex
+		succeeded  int
+		outOfStock int
+		failures   []error
+	)
+
+	start := make(chan struct{})
+	for _, userID := range userIDs {
+		wg.Add(1)
+		go func(userID uint) {
+			defer wg.Done()
+			<-start
+
+			_, err := orderService.CreateOrder(userID, &dto.CreateOrderRequest{PaymentToken: "tok_visa"})
+
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == nil:
+				succeeded++
+			case errors.Is(err, ErrInsufficientStock):
+				outOfStock++
+			default:
+				failures = append(failures, err)
+			}
+		}(userID)
+	}
+
+	close(start)
+	wg.Wait()
+
+	for _, err := range failures {
+		t.Errorf("unexpected checkout error: %v", err)
+	}
+
+	var final models.Product
+	if err := db.First(&final, product.ID).Error; err != nil {
+		t.Fatalf("failed to reload product: %v", err)
+	}
+
+	var ordered int64
+	if err := db.Model(&models.OrderItem{}).
+		Joins("JOIN orders ON orders.id = order_items.order_id").
+		Where("order_items.product_id = ? AND orders.status <> ?", product.ID, models.OrderStatusCancelled).
+		Select("COALESCE(SUM(order_items.quantity), 0)").
+		Scan(&ordered).Error; err != nil {
+		t.Fatalf("failed to sum ordered quantity: %v", err)
+	}
+
+	if final.Stock < 0 {
+		t.Errorf("final stock = %d, want >= 0", final.Stock)
+	}
+
+	if got := int64(final.Stock) + ordered; got != stock {
+		t.Errorf("final stock + ordered quantity = %d, want %d", got, stock)
+	}
+
+	if want := min(buyers, stock/quantity); succeeded != want {
+		t.Errorf("succeeded orders = %d, want %d", succeeded, want)
+	}
+
+	if want := buyers - min(buyers, stock/quantity); outOfStock != want {
+		t.Errorf("insufficient stock errors = %d, want %d", outOfStock, want)
+	}
+
+	var outOfStockEvents int64
+	if err := db.Model(&models.OutboxEvent{}).
+		Where("event_type = ? AND (envelope->'payload'->>'product_id')::int = ?", schema.ProductOutOfStock, product.ID).
+		Count(&outOfStockEvents).Error; err != nil {
+		t.Fatalf("failed to count stock alerts: %v", err)
+	}
+
+	if outOfStockEvents > 1 {
+		t.Errorf("out of stock events = %d, want at most 1", outOfStockEvents)
+	}
+}
+
+// TestCancelAbandonedCheckouts leaves a checkout awaiting its payment past the
+// authorization window and checks that it is cancelled and stops blocking the cart.
+func TestCancelAbandonedCheckouts(t *testing.T) {
+	db := openTestDB(t)
+
+	const stock = 5
+
+	product, userIDs := seedCheckout(t, db, 1, stock, 2)
+
+	paymentService := NewPaymentService(providers.NewFakePaymentProvider(), "USD")
+	inventoryService := NewInventoryService(db, 15*time.Minute)
+	orderService := NewOrderService(db, paymentService, inventoryService, 30*time.Minute, false)
+
+	checkout, err := orderService.placeOrder(userIDs[0])
+	if err != nil {
+		t.Fatalf("placeOrder() error = %v", err)
+	}
+
+	if err := db.Model(checkout.order).Update("created_at", time.Now().Add(-time.Hour)).Error; err != nil {
+		t.Fatalf("failed to age order: %v", err)
+	}
+
+	if _, err := orderService.CancelAbandonedCheckouts(); err != nil {
+		t.Fatalf("CancelAbandonedCheckouts() error = %v", err)
+	}
+
+	var order models.Order
+	if err := db.Preload("Payment").First(&order, checkout.order.ID).Error; err != nil {
+		t.Fatalf("failed to reload order: %v", err)
+	}
+
+	if order.Status != models.OrderStatusCancelled {
+		t.Errorf("order status = %s, want %s", order.Status, models.OrderStatusCancelled)
+	}
+	if order.Payment == nil || order.Payment.Status != models.PaymentStatusFailed {
+		t.Errorf("payment = %+v, want a failed payment", order.Payment)
+	}
+
+	var final models.Product
+	if err := db.First(&final, product.ID).Error; err != nil {
+		t.Fatalf("failed to reload product: %v", err)
+	}
+
+	if final.Stock != stock {
+		t.Errorf("final stock = %d, want %d", final.Stock, stock)
+	}
+
+	if _, err := orderService.CreateOrder(userIDs[0], &dto.CreateOrderRequest{PaymentToken: "tok_visa"}); err != nil {
+		t.Errorf("CreateOrder() after the abandoned checkout error = %v", err)
+	}
+}
+
+// TestOrderStatusChangesSettlePayment confirms and then cancels an order, and
+// checks that its payment is captured and refunded once each change commits.
+func TestOrderStatusChangesSettlePayment(t *testing.T) {
+	db := openTestDB(t)
+
+	_, userIDs := seedCheckout(t, db, 1, 5, 1)
+
+	paymentService := NewPaymentService(providers.NewFakePaymentProvider(), "USD")
+	inventoryService := NewInventoryService(db, 15*time.Minute)
+	orderService := NewOrderService(db, paymentService, inventoryService, 30*time.Minute, false)
+
+	order, err := orderService.CreateOrder(userIDs[0], &dto.CreateOrderRequest{PaymentToken: "tok_visa"})
+	if err != nil {
+		t.Fatalf("CreateOrder() error = %v", err)
+	}
+
+	for _, step := range []struct {
+		status  models.OrderStatus
+		payment models.PaymentStatus
+	}{
+		{models.OrderStatusConfirmed, models.PaymentStatusCaptured},
+		{models.OrderStatusCancelled, models.PaymentStatusRefunded},
+	} {
+		response, err := orderService.UpdateOrderStatus(userIDs[0], order.ID, &dto.UpdateOrderStatusRequest{Status: string(step.status)})
+		if err != nil {
+			t.Fatalf("UpdateOrderStatus(%s) error = %v", step.status, err)
+		}
+
+		if response.Payment == nil || response.Payment.Status != string(step.payment) {
+			t.Errorf("payment after %s = %+v, want %s", step.status, response.Payment, step.payment)
+		}
+	}
+}
+
+// seedCheckout creates a product and buyers whose carts hold quantity of it.
+// Everything is deleted again when the test ends.
+func seedCheckout(t *testing.T, db *gorm.DB, buyers, stock, quantity int) (*models.Product, []uint) {
+	t.Helper()
+
+	runID := uuid.New().String()[:8]
+
+	category := models.Category{Name: "checkout-test-" + runID}
+	if err := db.Create(&category).Error; err != nil {
+		t.Fatalf("failed to create category: %v", err)
+	}
+
+	product := models.Product{
+		CategoryID: category.ID,
+		Name:       "Checkout test product " + runID,
+		Price:      9.99,
+		Stock:      stock,
+		SKU:        "CHECKOUT-" + runID,
+	}
+	if err := db.Create(&product).Error; err != nil {
+		t.Fatalf("failed to create product: %v", err)
+	}
+
+	userIDs := make([]uint, 0, buyers)
+	t.Cleanup(func() {
+		if err := deleteCheckout(db, &product, userIDs); err != nil {
+			t.Errorf("failed to clean up checkout data: %v", err)
+		}
+	})
+
+	for i := range buyers {
+		user := models.User{
+			Email:     fmt.Sprintf("checkout-%s-%d@example.com", runID, i),
+			Password:  "not-a-real-hash",
+			FirstName: "Checkout",
+			LastName:  "Buyer",
+			IsActive:  true,
+		}
+		if err := db.Create(&user).Error; err != nil {
+			t.Fatalf("failed to create user: %v", err)
+		}
+		userIDs = append(userIDs, user.ID)
+
+		cart := models.Cart{UserID: user.ID}
+		if err := db.Create(&cart).Error; err != nil {
+			t.Fatalf("failed to create cart: %v", err)
+		}
+
+		if err := db.Create(&models.CartItem{CartID: cart.ID, ProductID: product.ID, Quantity: quantity}).Error; err != nil {
+			t.Fatalf("failed to create cart item: %v", err)
+		}
+	}
+
+	return &product, userIDs
+}
+
+func deleteCheckout(db *gorm.DB, product *models.Product, userIDs []uint) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("event_type IN ? AND (envelope->'payload'->>'product_id')::int = ?",
+			[]string{schema.ProductLowStock, schema.ProductOutOfStock}, product.ID).
+			Delete(&models.OutboxEvent{}).Error; err != nil {
+			return err
+		}
+
+		if len(userIDs) > 0 {
+			if err := tx.Where("event_type = ? AND (envelope->'payload'->>'user_id')::int IN ?", schema.OrderCreated, userIDs).
+				Delete(&models.OutboxEvent{}).Error; err != nil {
+				return err
+			}
+
+			if err := tx.Unscoped().Where("id IN ?", userIDs).Delete(&models.User{}).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Unscoped().Delete(&models.Product{}, product.ID).Error; err != nil {
+			return err
+		}
+
+		return tx.Unscoped().Delete(&models.Category{}, product.CategoryID).Error
+	})
+}
@@ -0,0 +1,53 @@
+package services
+
+import (
+	"fmt"
+
+	"github.com/tomimandalaputra/e-commerce-go/internal/models"
+)
+
+// orderTransitions lists the statuses an order may move to from each status.
+// Delivered and cancelled are terminal.
+var orderTransitions = map[models.OrderStatus][]models.OrderStatus{
+	models.OrderStatusPendingPayment: {models.OrderStatusPending, models.OrderStatusCancelled},
+	models.OrderStatusPending:        {models.OrderStatusConfirmed, models.OrderStatusCancelled},
+	models.OrderStatusConfirmed:      {models.OrderStatusShipped, models.OrderStatusCancelled},
+	models.OrderStatusShipped:        {models.OrderStatusDelivered},
+	models.OrderStatusDelivered:      {},
+	models.OrderStatusCancelled:      {},
+}
+
+// customerCancellableStatuses are the statuses in which a customer may still cancel their own order.
+var customerCancellableStatuses = map[models.OrderStatus]bool{
+	models.OrderStatusPending:   true,
+	models.OrderStatusConfirmed: true,
+}
+
+// CanTransitionOrder reports whether an order may move from one status to another.
+func CanTransitionOrder(from, to models.OrderStatus) bool {
+	for _, next := range orderTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsValidOrderStatus reports whether status is a known order status.
+func IsValidOrderStatus(status models.OrderStatus) bool {
+	_, ok := orderTransitions[status]
+	return ok
+}
+
+func validateOrderTransition(from, to models.OrderStatus) error {
+	if !IsValidOrderStatus(to) {
+		return fmt.Errorf("%w: unknown status %q", ErrInvalidOrderTransition, to)
+	}
+
+	if !CanTransitionOrder(from, to) {
+		return fmt.Errorf("%w: %s to %s", ErrInvalidOrderTransition, from, to)
+	}
+
+	return nil
+}
@@ -0,0 +1,211 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/tomimandalaputra/e-commerce-go/internal/interfaces"
+	"github.com/tomimandalaputra/e-commerce-go/internal/models"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrPaymentDeclined is returned when the payment provider declines an authorization.
+	ErrPaymentDeclined = errors.New("payment declined")
+	// ErrPaymentNotFound is returned when no payment matches a provider reference.
+	ErrPaymentNotFound = errors.New("payment not found")
+)
+
+type PaymentService struct {
+	provider interfaces.PaymentProvider
+	currency string
+}
+
+func NewPaymentService(provider interfaces.PaymentProvider, currency string) *PaymentService {
+	return &PaymentService{
+		provider: provider,
+		currency: currency,
+	}
+}
+
+// CreatePayment stores a pending payment for the order total. The provider is
+// asked to authorize it afterwards, outside the transaction, by AuthorizePayment.
+func (s *PaymentService) CreatePayment(tx *gorm.DB, order *models.Order) (*models.Payment, error) {
+	payment := models.Payment{
+		OrderID:  order.ID,
+		Provider: s.provider.Name(),
+		Amount:   order.TotalAmount,
+		Currency: s.currency,
+		Status:   models.PaymentStatusPending,
+	}
+
+	if err := tx.Create(&payment).Error; err != nil {
+		return nil, err
+	}
+
+	return &payment, nil
+}
+
+// AuthorizePayment asks the payment provider to authorize a pending payment and
+// sets its status from the outcome; RecordPayment stores it. A declined
+// authorization returns ErrPaymentDeclined.
+// It does not touch the database, so no transaction is held open while the
+// provider responds.
+func (s *PaymentService) AuthorizePayment(payment *models.Payment, token string) error {
+	result, err := s.provider.Authorize(&interfaces.PaymentRequest{
+		OrderID:  payment.OrderID,
+		Amount:   payment.Amount,
+		Currency: payment.Currency,
+		Token:    token,
+	})
+	if err != nil {
+		payment.Status = models.PaymentStatusFailed
+		payment.FailureReason = err.Error()
+		return fmt.Errorf("payment authorization failed: %w", err)
+	}
+
+	payment.ProviderPaymentID = result.ProviderPaymentID
+
+	if !result.Approved {
+		payment.Status = models.PaymentStatusDeclined
+		payment.FailureReason = result.DeclineReason
+		return fmt.Errorf("%w: %s", ErrPaymentDeclined, result.DeclineReason)
+	}
+
+	payment.Status = models.PaymentStatusAuthorized
+	return nil
+}
+
+// VoidPayment voids an authorization that will never be captured because its
+// order could not be completed. When the provider fails, the payment is left
+// void_pending with the error as its failure reason, to be voided again later.
+func (s *PaymentService) VoidPayment(payment *models.Payment) error {
+	payment.Status = models.PaymentStatusVoidPending
+	return s.Settle(payment)
+}
+
+// RecordPayment stores the outcome of AuthorizePayment or VoidPayment.
+func (s *PaymentService) RecordPayment(tx *gorm.DB, payment *models.Payment) error {
+	return tx.Model(payment).Updates(map[string]any{
+		"provider_payment_id": payment.ProviderPaymentID,
+		"status":              payment.Status,
+		"failure_reason":      payment.FailureReason,
+	}).Error
+}
+
+// RequestCapture marks the authorized payment of an order capture_pending.
+// The provider is asked to capture it by Settle once the transaction has
+// committed. Orders without an authorized payment are left untouched.
+func (s *PaymentService) RequestCapture(tx *gorm.DB, orderID uint) error {
+	payment, err := s.findPayment(tx, orderID, models.PaymentStatusAuthorized)
+	if err != nil || payment == nil {
+		return err
+	}
+
+	return tx.Model(payment).Update("status", models.PaymentStatusCapturePending).Error
+}
+
+// RequestRelease marks the payment of a cancelled order to be given back to
+// the customer: an authorized payment is to be voided and a captured one
+// refunded, by Settle once the transaction has committed.
+func (s *PaymentService) RequestRelease(tx *gorm.DB, orderID uint) error {
+	payment, err := s.findPayment(tx, orderID, models.PaymentStatusAuthorized, models.PaymentStatusCaptured)
+	if err != nil || payment == nil {
+		return err
+	}
+
+	status := models.PaymentStatusRefundPending
+	if payment.Status == models.PaymentStatusAuthorized {
+		status = models.PaymentStatusVoidPending
+	}
+
+	return tx.Model(payment).Update("status", status).Error
+}
+
+// Settle asks the provider to carry out the capture, void or refund a payment
+// is pending for, and sets its status from the outcome; RecordSettlement
+// stores it. A failure is kept as the failure reason and the payment stays
+// pending. Like AuthorizePayment it does not touch the database.
+func (s *PaymentService) Settle(payment *models.Payment) error {
+	var (
+		action  string
+		settled models.PaymentStatus
+		err     error
+	)
+
+	switch payment.Status {
+	case models.PaymentStatusCapturePending:
+		action, settled = "capture", models.PaymentStatusCaptured
+		_, err = s.provider.Capture(payment.ProviderPaymentID, payment.Amount)
+	case models.PaymentStatusVoidPending:
+		action, settled = "void", models.PaymentStatusVoided
+		_, err = s.provider.Void(payment.ProviderPaymentID)
+	case models.PaymentStatusRefundPending:
+		action, settled = "refund", models.PaymentStatusRefunded
+		_, err = s.provider.Refund(payment.ProviderPaymentID, payment.Amount)
+	default:
+		return nil
+	}
+
+	if err != nil {
+		payment.FailureReason = action + " failed: " + err.Error()
+		return fmt.Errorf("payment %s failed: %w", action, err)
+	}
+
+	payment.Status = settled
+	payment.FailureReason = ""
+	return nil
+}
+
+// RecordSettlement stores the outcome of Settle for a payment that was pending
+// with status, unless it has left that status in the meantime.
+func (s *PaymentService) RecordSettlement(db *gorm.DB, payment *models.Payment, status models.PaymentStatus) error {
+	return db.Model(&models.Payment{}).
+		Where("id = ? AND status = ?", payment.ID, status).
+		Updates(map[string]any{
+			"status":         payment.Status,
+			"failure_reason": payment.FailureReason,
+		}).Error
+}
+
+// FindByProviderPaymentID returns the payment matching the provider's reference.
+func (s *PaymentService) FindByProviderPaymentID(tx *gorm.DB, providerPaymentID string) (*models.Payment, error) {
+	var payment models.Payment
+	if err := tx.Where("provider = ? AND provider_payment_id = ?", s.provider.Name(), providerPaymentID).
+		First(&payment).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrPaymentNotFound
+		}
+		return nil, err
+	}
+
+	return &payment, nil
+}
+
+// MarkCaptured records that the provider has captured the payment on its side.
+func (s *PaymentService) MarkCaptured(tx *gorm.DB, payment *models.Payment) error {
+	return tx.Model(payment).Update("status", models.PaymentStatusCaptured).Error
+}
+
+// MarkFailed records that the provider could not complete the payment.
+func (s *PaymentService) MarkFailed(tx *gorm.DB, payment *models.Payment, reason string) error {
+	return tx.Model(payment).Updates(map[string]any{
+		"status":         models.PaymentStatusFailed,
+		"failure_reason": reason,
+	}).Error
+}
+
+func (s *PaymentService) findPayment(tx *gorm.DB, orderID uint, statuses ...models.PaymentStatus) (*models.Payment, error) {
+	var payment models.Payment
+	err := tx.Where("order_id = ? AND status IN ?", orderID, statuses).
+		Order("created_at DESC").
+		First(&payment).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &payment, nil
+}
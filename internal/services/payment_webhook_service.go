@@ -0,0 +1,112 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/tomimandalaputra/e-commerce-go/internal/interfaces"
+	"github.com/tomimandalaputra/e-commerce-go/internal/models"
+	"github.com/tomimandalaputra/e-commerce-go/internal/utils"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	// ErrUnknownPaymentProvider is returned for webhooks addressed to a provider that is not configured.
+	ErrUnknownPaymentProvider = errors.New("unknown payment provider")
+	// ErrInvalidWebhookSignature is returned when the webhook HMAC signature does not match.
+	ErrInvalidWebhookSignature = errors.New("invalid webhook signature")
+	// ErrInvalidWebhookPayload is returned when the provider cannot parse the webhook body.
+	ErrInvalidWebhookPayload = errors.New("invalid webhook payload")
+)
+
+type PaymentWebhookService struct {
+	db           *gorm.DB
+	provider     interfaces.PaymentProvider
+	secret       string
+	orderService *OrderService
+}
+
+func NewPaymentWebhookService(db *gorm.DB, provider interfaces.PaymentProvider, secret string, orderService *OrderService) *PaymentWebhookService {
+	return &PaymentWebhookService{
+		db:           db,
+		provider:     provider,
+		secret:       secret,
+		orderService: orderService,
+	}
+}
+
+// HandleWebhook verifies, stores and processes a payment provider webhook.
+// Every event is stored once per provider event ID; replays of an event that
+// was already processed or ignored are acknowledged without side effects.
+func (s *PaymentWebhookService) HandleWebhook(providerName, signature string, payload []byte) error {
+	if providerName != s.provider.Name() {
+		return ErrUnknownPaymentProvider
+	}
+
+	if !utils.VerifySignature(s.secret, payload, signature) {
+		return ErrInvalidWebhookSignature
+	}
+
+	event, err := s.provider.ParseWebhook(payload)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidWebhookPayload, err)
+	}
+
+	record := models.WebhookEvent{
+		Provider:  providerName,
+		EventID:   event.EventID,
+		EventType: event.Type,
+		Payload:   string(payload),
+		Status:    models.WebhookEventStatusReceived,
+	}
+	if err := s.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&record).Error; err != nil {
+		return err
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		var stored models.WebhookEvent
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("provider = ? AND event_id = ?", providerName, event.EventID).
+			First(&stored).Error; err != nil {
+			return err
+		}
+
+		if stored.Status == models.WebhookEventStatusProcessed || stored.Status == models.WebhookEventStatusIgnored {
+			return nil
+		}
+
+		status := models.WebhookEventStatusProcessed
+		switch event.Type {
+		case interfaces.PaymentEventSucceeded, interfaces.PaymentEventFailed:
+			err := s.orderService.ApplyPaymentEvent(tx, event)
+			if errors.Is(err, ErrPaymentNotFound) {
+				status = models.WebhookEventStatusIgnored
+			} else if err != nil {
+				return err
+			}
+		default:
+			status = models.WebhookEventStatusIgnored
+		}
+
+		now := time.Now()
+		return tx.Model(&stored).Updates(map[string]any{
+			"status":       status,
+			"error":        "",
+			"processed_at": &now,
+		}).Error
+	})
+
+	if err != nil {
+		s.db.Model(&models.WebhookEvent{}).
+			Where("provider = ? AND event_id = ?", providerName, event.EventID).
+			Updates(map[string]any{
+				"status": models.WebhookEventStatusFailed,
+				"error":  err.Error(),
+			})
+		return err
+	}
+
+	return nil
+}
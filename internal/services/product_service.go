@@ -2,17 +2,24 @@ package services
 
 import (
 	"github.com/tomimandalaputra/e-commerce-go/internal/dto"
+	"github.com/tomimandalaputra/e-commerce-go/internal/events"
+	"github.com/tomimandalaputra/e-commerce-go/internal/events/schema"
 	"github.com/tomimandalaputra/e-commerce-go/internal/models"
 	"github.com/tomimandalaputra/e-commerce-go/internal/utils"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type ProductService struct {
-	db *gorm.DB
+	db               *gorm.DB
+	inventoryService *InventoryService
 }
 
-func NewProductService(db *gorm.DB) *ProductService {
-	return &ProductService{db: db}
+func NewProductService(db *gorm.DB, inventoryService *InventoryService) *ProductService {
+	return &ProductService{
+		db:               db,
+		inventoryService: inventoryService,
+	}
 }
 
 func (s *ProductService) CreateCategory(req *dto.CreateCategoryRequest) (*dto.CategoryResponse, error) {
@@ -86,17 +93,34 @@ func (s *ProductService) DeleteCategory(id uint) error {
 	return s.db.Delete(&models.Category{}, id).Error
 }
 
-func (s *ProductService) CreateProduct(req *dto.CreateProductRequest) (*dto.ProductResponse, error) {
+func (s *ProductService) CreateProduct(actorID uint, req *dto.CreateProductRequest) (*dto.ProductResponse, error) {
 	product := models.Product{
-		CategoryID:  req.CategoryID,
-		Name:        req.Name,
-		Description: req.Description,
-		Price:       req.Price,
-		Stock:       req.Stock,
-		SKU:         req.SKU,
+		CategoryID:        req.CategoryID,
+		Name:              req.Name,
+		Description:       req.Description,
+		SKU:               req.SKU,
+		Price:             req.Price,
+		LowStockThreshold: models.DefaultLowStockThreshold,
+	}
+
+	if req.LowStockThreshold != nil {
+		product.LowStockThreshold = *req.LowStockThreshold
 	}
 
-	if err := s.db.Create(&product).Error; err != nil {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&product).Error; err != nil {
+			return err
+		}
+
+		// Opening stock goes through the ledger like every other stock change
+		return s.inventoryService.AdjustStock(tx, product.ID, req.Stock, &StockChange{
+			Reason:  models.InventoryReasonManualAdjustment,
+			ActorID: &actorID,
+			Note:    "initial stock",
+		})
+	})
+
+	if err != nil {
 		return nil, err
 	}
 
@@ -125,9 +149,19 @@ func (s *ProductService) GetProducts(page, limit int) ([]dto.ProductResponse, *u
 		return nil, nil, err
 	}
 
+	productIDs := make([]uint, len(products))
+	for i := range products {
+		productIDs[i] = products[i].ID
+	}
+
+	reserved, err := s.inventoryService.ReservedQuantities(s.db, productIDs, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	response := make([]dto.ProductResponse, len(products))
 	for i := range products {
-		response[i] = s.convertToProductResponse(&products[i])
+		response[i] = s.convertToProductResponse(&products[i], reserved[products[i].ID])
 	}
 
 	totalPages := int((total + int64(limit) - 1) / int64(limit))
@@ -147,26 +181,60 @@ func (s *ProductService) GetProduct(id uint) (*dto.ProductResponse, error) {
 		return nil, err
 	}
 
-	response := s.convertToProductResponse(&product)
+	reserved, err := s.inventoryService.ReservedQuantities(s.db, []uint{product.ID}, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	response := s.convertToProductResponse(&product, reserved[product.ID])
 	return &response, nil
 }
 
-func (s *ProductService) UpdateProduct(id uint, req *dto.UpdateProductRequest) (*dto.ProductResponse, error) {
-	var product models.Product
-	if err := s.db.First(&product, id).Error; err != nil {
-		return nil, err
-	}
+func (s *ProductService) UpdateProduct(id, actorID uint, req *dto.UpdateProductRequest) (*dto.ProductResponse, error) {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var product models.Product
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&product, id).Error; err != nil {
+			return err
+		}
 
-	product.CategoryID = req.CategoryID
-	product.Name = req.Name
-	product.Description = req.Description
-	product.Price = req.Price
-	product.Stock = req.Stock
-	if req.IsActive != nil {
-		product.IsActive = *req.IsActive
-	}
+		oldPrice := product.Price
+
+		product.CategoryID = req.CategoryID
+		product.Name = req.Name
+		product.Description = req.Description
+		product.Price = req.Price
+		if req.IsActive != nil {
+			product.IsActive = *req.IsActive
+		}
+		if req.LowStockThreshold != nil {
+			product.LowStockThreshold = *req.LowStockThreshold
+		}
+
+		if err := tx.Omit("Stock").Save(&product).Error; err != nil {
+			return err
+		}
+
+		if product.Price != oldPrice {
+			if err := events.Enqueue(tx, schema.ProductPriceChangedV1{
+				ProductID: product.ID,
+				SKU:       product.SKU,
+				OldPrice:  oldPrice,
+				NewPrice:  product.Price,
+				ActorID:   actorID,
+			}, ""); err != nil {
+				return err
+			}
+		}
+
+		// Stock is never overwritten directly; the difference is recorded in the ledger
+		return s.inventoryService.AdjustStock(tx, product.ID, req.Stock-product.Stock, &StockChange{
+			Reason:  models.InventoryReasonManualAdjustment,
+			ActorID: &actorID,
+			Note:    "product update",
+		})
+	})
 
-	if err := s.db.Save(&product).Error; err != nil {
+	if err != nil {
 		return nil, err
 	}
 
@@ -191,7 +259,7 @@ func (s *ProductService) AddProductImage(productID uint, url, altText string) er
 	return s.db.Create(&image).Error
 }
 
-func (s *ProductService) convertToProductResponse(product *models.Product) dto.ProductResponse {
+func (s *ProductService) convertToProductResponse(product *models.Product, reserved int) dto.ProductResponse {
 	images := make([]dto.ProductImageResponse, len(product.Images))
 	for i := range product.Images {
 		images[i] = dto.ProductImageResponse{
@@ -210,6 +278,7 @@ func (s *ProductService) convertToProductResponse(product *models.Product) dto.P
 		Description: product.Description,
 		Price:       product.Price,
 		Stock:       product.Stock,
+		Available:   max(product.Stock-reserved, 0),
 		SKU:         product.SKU,
 		IsActive:    product.IsActive,
 		Category: dto.CategoryResponse{
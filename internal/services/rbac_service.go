@@ -0,0 +1,221 @@
+package services
+
+import (
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/tomimandalaputra/e-commerce-go/internal/config"
+	"github.com/tomimandalaputra/e-commerce-go/internal/dto"
+	"github.com/tomimandalaputra/e-commerce-go/internal/events"
+	"github.com/tomimandalaputra/e-commerce-go/internal/events/schema"
+	"github.com/tomimandalaputra/e-commerce-go/internal/models"
+	"github.com/tomimandalaputra/e-commerce-go/internal/utils"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	// ErrRoleNotFound is returned when a role does not exist.
+	ErrRoleNotFound = errors.New("role not found")
+	// ErrRoleNotAssigned is returned when revoking a role the user does not hold.
+	ErrRoleNotAssigned = errors.New("role is not assigned to the user")
+	// ErrCannotChangeOwnRoles is returned when a user tries to assign or revoke their own roles.
+	ErrCannotChangeOwnRoles = errors.New("you cannot change your own roles")
+)
+
+// RBACService decides what staff accounts may do. Users hold roles and roles
+// grant permissions; the permissions of a user are cached in memory for a
+// short TTL, which bounds how long a change made on another API instance
+// takes to apply here.
+type RBACService struct {
+	db       *gorm.DB
+	cache    *utils.LRU[uint, []string]
+	cacheTTL time.Duration
+}
+
+func NewRBACService(db *gorm.DB, cfg *config.AuthConfig) *RBACService {
+	return &RBACService{
+		db:       db,
+		cache:    utils.NewLRU[uint, []string](cfg.PermissionCacheSize),
+		cacheTTL: cfg.PermissionCacheTTL,
+	}
+}
+
+// HasPermission reports whether any role of the user grants permission.
+func (s *RBACService) HasPermission(userID uint, permission string) (bool, error) {
+	permissions, err := s.permissions(userID)
+	if err != nil {
+		return false, err
+	}
+
+	return slices.Contains(permissions, permission), nil
+}
+
+// IsStaff reports whether the user holds a role that grants any permission.
+func (s *RBACService) IsStaff(userID uint) (bool, error) {
+	permissions, err := s.permissions(userID)
+	return len(permissions) > 0, err
+}
+
+func (s *RBACService) permissions(userID uint) ([]string, error) {
+	if permissions, ok := s.cache.Get(userID); ok {
+		return permissions, nil
+	}
+
+	var permissions []string
+	if err := s.db.Model(&models.RoleAssignment{}).
+		Distinct("permissions.name").
+		Joins("JOIN role_permissions ON role_permissions.role_id = user_roles.role_id").
+		Joins("JOIN permissions ON permissions.id = role_permissions.permission_id").
+		Where("user_roles.user_id = ?", userID).
+		Pluck("permissions.name", &permissions).Error; err != nil {
+		return nil, err
+	}
+
+	s.cache.Set(userID, permissions, s.cacheTTL)
+	return permissions, nil
+}
+
+// GetRoles lists every role with its permissions.
+func (s *RBACService) GetRoles() ([]dto.RoleResponse, error) {
+	var roles []models.Role
+	if err := s.db.Preload("Permissions").Order("name").Find(&roles).Error; err != nil {
+		return nil, err
+	}
+
+	return s.convertToRoleResponses(roles), nil
+}
+
+// GetUserRoles lists the roles assigned to a user.
+func (s *RBACService) GetUserRoles(userID uint) ([]dto.RoleResponse, error) {
+	if err := s.db.Select("id").First(&models.User{}, userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	var roles []models.Role
+	if err := s.db.Preload("Permissions").
+		Joins("JOIN user_roles ON user_roles.role_id = roles.id").
+		Where("user_roles.user_id = ?", userID).
+		Order("roles.name").
+		Find(&roles).Error; err != nil {
+		return nil, err
+	}
+
+	return s.convertToRoleResponses(roles), nil
+}
+
+// AssignRole grants a role to a user. Assigning a role the user already holds
+// does nothing.
+func (s *RBACService) AssignRole(actorID, userID uint, req *dto.AssignRoleRequest) ([]dto.RoleResponse, error) {
+	if actorID == userID {
+		return nil, ErrCannotChangeOwnRoles
+	}
+
+	role, err := s.findUserAndRole(userID, req.Role)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&models.RoleAssignment{
+			UserID:     userID,
+			RoleID:     role.ID,
+			AssignedBy: &actorID,
+		})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+
+		return events.Enqueue(tx, schema.UserRoleAssignedV1{
+			UserID:  userID,
+			Role:    role.Name,
+			ActorID: actorID,
+		}, "")
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	s.cache.Delete(userID)
+	return s.GetUserRoles(userID)
+}
+
+// RevokeRole takes a role away from a user.
+func (s *RBACService) RevokeRole(actorID, userID uint, roleName string) error {
+	if actorID == userID {
+		return ErrCannotChangeOwnRoles
+	}
+
+	role, err := s.findUserAndRole(userID, roleName)
+	if err != nil {
+		return err
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("user_id = ? AND role_id = ?", userID, role.ID).Delete(&models.RoleAssignment{})
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return ErrRoleNotAssigned
+		}
+
+		return events.Enqueue(tx, schema.UserRoleRevokedV1{
+			UserID:  userID,
+			Role:    role.Name,
+			ActorID: actorID,
+		}, "")
+	})
+
+	if err != nil {
+		return err
+	}
+
+	s.cache.Delete(userID)
+	return nil
+}
+
+// findUserAndRole checks that the user exists and returns the role named roleName.
+func (s *RBACService) findUserAndRole(userID uint, roleName string) (*models.Role, error) {
+	if err := s.db.Select("id").First(&models.User{}, userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	var role models.Role
+	if err := s.db.Where("name = ?", roleName).First(&role).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrRoleNotFound
+		}
+		return nil, err
+	}
+
+	return &role, nil
+}
+
+func (s *RBACService) convertToRoleResponses(roles []models.Role) []dto.RoleResponse {
+	responses := make([]dto.RoleResponse, len(roles))
+	for i := range roles {
+		permissions := make([]string, len(roles[i].Permissions))
+		for j := range roles[i].Permissions {
+			permissions[j] = roles[i].Permissions[j].Name
+		}
+		slices.Sort(permissions)
+
+		responses[i] = dto.RoleResponse{
+			ID:          roles[i].ID,
+			Name:        roles[i].Name,
+			Description: roles[i].Description,
+			Permissions: permissions,
+		}
+	}
+	return responses
+}
@@ -0,0 +1,123 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/tomimandalaputra/e-commerce-go/internal/config"
+	"github.com/tomimandalaputra/e-commerce-go/internal/models"
+	"github.com/tomimandalaputra/e-commerce-go/internal/utils"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// TokenRevocationService keeps the denylist of access tokens that must stop
+// working before they expire. Lookups go through an in-memory LRU cache so
+// authenticated requests rarely hit the database.
+type TokenRevocationService struct {
+	db       *gorm.DB
+	cache    *utils.LRU[string, bool]
+	cacheTTL time.Duration
+}
+
+func NewTokenRevocationService(db *gorm.DB, cfg *config.AuthConfig) *TokenRevocationService {
+	return &TokenRevocationService{
+		db:       db,
+		cache:    utils.NewLRU[string, bool](cfg.RevocationCacheSize),
+		cacheTTL: cfg.RevocationCacheTTL,
+	}
+}
+
+// IsRevoked reports whether the access token with the given jti was revoked.
+// Tokens found not revoked are cached for the cache TTL only, which bounds how
+// long a revocation made by another API instance takes to apply here.
+func (s *TokenRevocationService) IsRevoked(tokenID string) (bool, error) {
+	if revoked, ok := s.cache.Get(tokenID); ok {
+		return revoked, nil
+	}
+
+	var count int64
+	if err := s.db.Model(&models.RevokedAccessToken{}).Where("token_id = ?", tokenID).Count(&count).Error; err != nil {
+		return false, err
+	}
+
+	revoked := count > 0
+	s.cache.Set(tokenID, revoked, s.cacheTTL)
+	return revoked, nil
+}
+
+// RevokeFamily revokes every unexpired access token issued in a refresh token family.
+func (s *TokenRevocationService) RevokeFamily(tx *gorm.DB, familyID string) error {
+	return s.revoke(tx, "family_id = ?", familyID)
+}
+
+// RevokeUser revokes every unexpired access token of a user.
+func (s *TokenRevocationService) RevokeUser(tx *gorm.DB, userID uint) error {
+	return s.revoke(tx, "user_id = ?", userID)
+}
+
+// revoke denylists the access tokens recorded on the matching refresh tokens.
+// Deleted refresh tokens are included, since their access tokens may still be
+// live. The cache is updated right away; if tx rolls back the tokens are only
+// rejected locally until their cache entry expires, which fails safe.
+func (s *TokenRevocationService) revoke(tx *gorm.DB, query string, args ...any) error {
+	var tokens []models.RefreshToken
+	if err := tx.Unscoped().Where(query, args...).
+		Where("access_token_id IS NOT NULL AND access_token_expires_at > ?", time.Now()).
+		Find(&tokens).Error; err != nil {
+		return err
+	}
+
+	if len(tokens) == 0 {
+		return nil
+	}
+
+	revoked := make([]models.RevokedAccessToken, len(tokens))
+	for i := range tokens {
+		revoked[i] = models.RevokedAccessToken{
+			TokenID:   tokens[i].AccessTokenID,
+			UserID:    tokens[i].UserID,
+			ExpiresAt: tokens[i].AccessTokenExpiresAt,
+		}
+	}
+
+	if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&revoked).Error; err != nil {
+		return err
+	}
+
+	for i := range revoked {
+		s.cache.Set(revoked[i].TokenID, true, time.Until(revoked[i].ExpiresAt))
+	}
+
+	return nil
+}
+
+// PurgeExpired deletes denylist entries of tokens that have expired anyway.
+func (s *TokenRevocationService) PurgeExpired() (int64, error) {
+	result := s.db.Where("expires_at <= ?", time.Now()).Delete(&models.RevokedAccessToken{})
+	return result.RowsAffected, result.Error
+}
+
+// StartPurger purges expired denylist entries every interval until ctx is cancelled.
+func (s *TokenRevocationService) StartPurger(ctx context.Context, interval time.Duration, logger *zerolog.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			purged, err := s.PurgeExpired()
+			if err != nil {
+				logger.Error().Err(err).Msg("failed to purge revoked access tokens")
+				continue
+			}
+
+			if purged > 0 {
+				logger.Info().Int64("purged", purged).Msg("purged expired revoked access tokens")
+			}
+		}
+	}
+}
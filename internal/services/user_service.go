@@ -1,18 +1,37 @@
 package services
 
 import (
+	"errors"
+	"time"
+
 	"github.com/tomimandalaputra/e-commerce-go/internal/dto"
+	"github.com/tomimandalaputra/e-commerce-go/internal/events"
+	"github.com/tomimandalaputra/e-commerce-go/internal/events/schema"
 	"github.com/tomimandalaputra/e-commerce-go/internal/models"
+	"github.com/tomimandalaputra/e-commerce-go/internal/utils"
 	"gorm.io/gorm"
 )
 
+var (
+	// ErrIncorrectPassword is returned when the current password given to change it is wrong.
+	ErrIncorrectPassword = errors.New("current password is incorrect")
+	// ErrSessionNotFound is returned when a session does not exist or belongs to another user.
+	ErrSessionNotFound = errors.New("session not found")
+	// ErrUserNotFound is returned when a user does not exist.
+	ErrUserNotFound = errors.New("user not found")
+	// ErrCannotDeactivateSelf is returned when an admin tries to deactivate their own account.
+	ErrCannotDeactivateSelf = errors.New("you cannot deactivate your own account")
+)
+
 type UserService struct {
-	db *gorm.DB
+	db                *gorm.DB
+	revocationService *TokenRevocationService
 }
 
-func NewUserService(db *gorm.DB) *UserService {
+func NewUserService(db *gorm.DB, revocationService *TokenRevocationService) *UserService {
 	return &UserService{
-		db: db,
+		db:                db,
+		revocationService: revocationService,
 	}
 }
 
@@ -23,13 +42,14 @@ func (s *UserService) GetProfile(userID uint) (*dto.UserResponse, error) {
 	}
 
 	return &dto.UserResponse{
-		ID:        userID,
-		Email:     user.Email,
-		FirstName: user.FirstName,
-		LastName:  user.LastName,
-		Phone:     user.Phone,
-		Role:      string(user.Role),
-		IsActive:  user.IsActive,
+		ID:            userID,
+		Email:         user.Email,
+		FirstName:     user.FirstName,
+		LastName:      user.LastName,
+		Phone:         user.Phone,
+		IsActive:      user.IsActive,
+		EmailVerified: user.EmailVerifiedAt != nil,
+		MFAEnabled:    user.TOTPEnabledAt != nil,
 	}, nil
 }
 
@@ -49,3 +69,136 @@ func (s *UserService) UpdateProfile(userID uint, req *dto.UpdateProfileRequest)
 
 	return s.GetProfile(userID)
 }
+
+// ChangePassword replaces the password after checking the current one and
+// signs the user out of every session.
+func (s *UserService) ChangePassword(userID uint, req *dto.ChangePasswordRequest) error {
+	var user models.User
+	if err := s.db.First(&user, userID).Error; err != nil {
+		return err
+	}
+
+	if !utils.CheckPassword(req.CurrentPassword, user.Password) {
+		return ErrIncorrectPassword
+	}
+
+	hashedPassword, err := utils.HashPassword(req.NewPassword)
+	if err != nil {
+		return err
+	}
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&user).Update("password", hashedPassword).Error; err != nil {
+			return err
+		}
+
+		if err := s.revocationService.RevokeUser(tx, userID); err != nil {
+			return err
+		}
+
+		if err := tx.Where("user_id = ?", userID).Delete(&models.RefreshToken{}).Error; err != nil {
+			return err
+		}
+
+		return events.Enqueue(tx, schema.UserPasswordChangedV1{
+			UserID: user.ID,
+			Email:  user.Email,
+		}, "")
+	})
+}
+
+// GetSessions lists the sessions of the user, newest first. A session is the
+// current, not yet rotated, refresh token of a family.
+func (s *UserService) GetSessions(userID uint) ([]dto.SessionResponse, error) {
+	var tokens []models.RefreshToken
+	if err := s.db.Where("user_id = ? AND rotated_at IS NULL AND expires_at > ?", userID, time.Now()).
+		Order("created_at DESC").
+		Find(&tokens).Error; err != nil {
+		return nil, err
+	}
+
+	response := make([]dto.SessionResponse, len(tokens))
+	for i := range tokens {
+		response[i] = dto.SessionResponse{
+			ID:        tokens[i].ID,
+			UserAgent: tokens[i].UserAgent,
+			IPAddress: tokens[i].IPAddress,
+			CreatedAt: tokens[i].CreatedAt,
+			ExpiresAt: tokens[i].ExpiresAt,
+		}
+	}
+
+	return response, nil
+}
+
+// RevokeSession deletes the refresh token family of a session so it can no longer be refreshed.
+func (s *UserService) RevokeSession(userID, sessionID uint) error {
+	var token models.RefreshToken
+	if err := s.db.Where("id = ? AND user_id = ?", sessionID, userID).First(&token).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrSessionNotFound
+		}
+		return err
+	}
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := s.revocationService.RevokeFamily(tx, token.FamilyID); err != nil {
+			return err
+		}
+
+		return tx.Where("family_id = ?", token.FamilyID).Delete(&models.RefreshToken{}).Error
+	})
+}
+
+// UpdateUserStatus activates or deactivates an account. Deactivation signs the
+// user out everywhere at once: refresh tokens are deleted and live access tokens revoked.
+func (s *UserService) UpdateUserStatus(actorID, userID uint, req *dto.UpdateUserStatusRequest) (*dto.UserResponse, error) {
+	if !*req.IsActive && actorID == userID {
+		return nil, ErrCannotDeactivateSelf
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.User{}).Where("id = ?", userID).Update("is_active", *req.IsActive)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return ErrUserNotFound
+		}
+
+		if *req.IsActive {
+			return nil
+		}
+
+		if err := s.revocationService.RevokeUser(tx, userID); err != nil {
+			return err
+		}
+
+		return tx.Where("user_id = ?", userID).Delete(&models.RefreshToken{}).Error
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetProfile(userID)
+}
+
+// UnlockUser lifts a login lockout and resets the failed login counter.
+func (s *UserService) UnlockUser(userID uint) (*dto.UserResponse, error) {
+	result := s.db.Model(&models.User{}).Where("id = ?", userID).Updates(map[string]any{
+		"failed_login_attempts": 0,
+		"login_not_before":      nil,
+		"locked_until":          nil,
+	})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return nil, ErrUserNotFound
+	}
+
+	return s.GetProfile(userID)
+}
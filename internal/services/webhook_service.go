@@ -0,0 +1,524 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/tomimandalaputra/e-commerce-go/internal/config"
+	"github.com/tomimandalaputra/e-commerce-go/internal/dto"
+	"github.com/tomimandalaputra/e-commerce-go/internal/events/schema"
+	"github.com/tomimandalaputra/e-commerce-go/internal/models"
+	"github.com/tomimandalaputra/e-commerce-go/internal/utils"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// maxWebhookResponseBody is how much of a receiver's response is kept in the delivery log
+	maxWebhookResponseBody = 1024
+	webhookUserAgent       = "e-commerce-go-webhooks/1.0"
+)
+
+var (
+	// ErrWebhookSubscriptionNotFound is returned when a webhook subscription does not exist.
+	ErrWebhookSubscriptionNotFound = errors.New("webhook subscription not found")
+	// ErrWebhookDeliveryNotFound is returned when a webhook delivery does not exist.
+	ErrWebhookDeliveryNotFound = errors.New("webhook delivery not found")
+	// ErrUnknownEventType is returned when subscribing to an event type that is never sent to webhooks.
+	ErrUnknownEventType = errors.New("unknown event type")
+)
+
+// WebhookService manages merchant webhook subscriptions and delivers domain
+// events to them as signed HTTP requests.
+type WebhookService struct {
+	db         *gorm.DB
+	config     *config.WebhookConfig
+	httpClient *http.Client
+}
+
+func NewWebhookService(db *gorm.DB, cfg *config.WebhookConfig, httpClient *http.Client) *WebhookService {
+	return &WebhookService{
+		db:         db,
+		config:     cfg,
+		httpClient: httpClient,
+	}
+}
+
+func (s *WebhookService) CreateSubscription(req *dto.CreateWebhookSubscriptionRequest) (*dto.WebhookSubscriptionResponse, error) {
+	if err := validateEventTypes(req.EventTypes); err != nil {
+		return nil, err
+	}
+
+	secret, err := generateWebhookSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	subscription := models.WebhookSubscription{
+		URL:         req.URL,
+		Secret:      secret,
+		EventTypes:  req.EventTypes,
+		Description: req.Description,
+		IsActive:    true,
+	}
+
+	if err := s.db.Create(&subscription).Error; err != nil {
+		return nil, err
+	}
+
+	// The secret is only ever shown once, when the subscription is created
+	response := s.convertToSubscriptionResponse(&subscription)
+	response.Secret = subscription.Secret
+	return &response, nil
+}
+
+func (s *WebhookService) GetSubscriptions(page, limit int) ([]dto.WebhookSubscriptionResponse, *utils.PaginationMeta, error) {
+	if page < 1 {
+		page = 1
+	}
+
+	if limit < 1 {
+		limit = 10
+	}
+
+	if limit > 100 {
+		limit = 100
+	}
+
+	offset := (page - 1) * limit
+	var subscriptions []models.WebhookSubscription
+	var total int64
+
+	s.db.Model(&models.WebhookSubscription{}).Count(&total)
+
+	if err := s.db.Order("id ASC").Offset(offset).Limit(limit).Find(&subscriptions).Error; err != nil {
+		return nil, nil, err
+	}
+
+	response := make([]dto.WebhookSubscriptionResponse, len(subscriptions))
+	for i := range subscriptions {
+		response[i] = s.convertToSubscriptionResponse(&subscriptions[i])
+	}
+
+	totalPages := int((total + int64(limit) - 1) / int64(limit))
+	meta := &utils.PaginationMeta{
+		Page:       page,
+		Limit:      limit,
+		Total:      total,
+		TotalPages: totalPages,
+	}
+
+	return response, meta, nil
+}
+
+func (s *WebhookService) GetSubscription(id uint) (*dto.WebhookSubscriptionResponse, error) {
+	subscription, err := s.findSubscription(s.db, id)
+	if err != nil {
+		return nil, err
+	}
+
+	response := s.convertToSubscriptionResponse(subscription)
+	return &response, nil
+}
+
+func (s *WebhookService) UpdateSubscription(id uint, req *dto.UpdateWebhookSubscriptionRequest) (*dto.WebhookSubscriptionResponse, error) {
+	if err := validateEventTypes(req.EventTypes); err != nil {
+		return nil, err
+	}
+
+	subscription, err := s.findSubscription(s.db, id)
+	if err != nil {
+		return nil, err
+	}
+
+	subscription.URL = req.URL
+	subscription.EventTypes = req.EventTypes
+	subscription.Description = req.Description
+	if req.IsActive != nil {
+		subscription.IsActive = *req.IsActive
+	}
+
+	if err := s.db.Save(subscription).Error; err != nil {
+		return nil, err
+	}
+
+	response := s.convertToSubscriptionResponse(subscription)
+	return &response, nil
+}
+
+func (s *WebhookService) DeleteSubscription(id uint) error {
+	result := s.db.Delete(&models.WebhookSubscription{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrWebhookSubscriptionNotFound
+	}
+
+	return nil
+}
+
+// GetDeliveries returns the delivery log of a subscription, newest first.
+func (s *WebhookService) GetDeliveries(subscriptionID uint, page, limit int) ([]dto.WebhookDeliveryResponse, *utils.PaginationMeta, error) {
+	if _, err := s.findSubscription(s.db, subscriptionID); err != nil {
+		return nil, nil, err
+	}
+
+	if page < 1 {
+		page = 1
+	}
+
+	if limit < 1 {
+		limit = 10
+	}
+
+	if limit > 100 {
+		limit = 100
+	}
+
+	offset := (page - 1) * limit
+	var deliveries []models.WebhookDelivery
+	var total int64
+
+	s.db.Model(&models.WebhookDelivery{}).Where("subscription_id = ?", subscriptionID).Count(&total)
+
+	if err := s.db.Where("subscription_id = ?", subscriptionID).
+		Order("created_at DESC, id DESC").
+		Offset(offset).Limit(limit).
+		Find(&deliveries).Error; err != nil {
+		return nil, nil, err
+	}
+
+	response := make([]dto.WebhookDeliveryResponse, len(deliveries))
+	for i := range deliveries {
+		response[i] = s.convertToDeliveryResponse(&deliveries[i])
+	}
+
+	totalPages := int((total + int64(limit) - 1) / int64(limit))
+	meta := &utils.PaginationMeta{
+		Page:       page,
+		Limit:      limit,
+		Total:      total,
+		TotalPages: totalPages,
+	}
+
+	return response, meta, nil
+}
+
+// GetDelivery returns a delivery with every attempt made for it.
+func (s *WebhookService) GetDelivery(subscriptionID uint, deliveryID uint64) (*dto.WebhookDeliveryResponse, error) {
+	var delivery models.WebhookDelivery
+	if err := s.db.Preload("Attempts", func(db *gorm.DB) *gorm.DB {
+		return db.Order("id ASC")
+	}).Where("id = ? AND subscription_id = ?", deliveryID, subscriptionID).First(&delivery).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrWebhookDeliveryNotFound
+		}
+		return nil, err
+	}
+
+	response := s.convertToDeliveryResponse(&delivery)
+	return &response, nil
+}
+
+// Redeliver queues a delivery to be sent again straight away with a fresh
+// retry budget. Earlier attempts stay in the log.
+func (s *WebhookService) Redeliver(subscriptionID uint, deliveryID uint64) (*dto.WebhookDeliveryResponse, error) {
+	result := s.db.Model(&models.WebhookDelivery{}).
+		Where("id = ? AND subscription_id = ?", deliveryID, subscriptionID).
+		Updates(map[string]any{
+			"status":          models.WebhookDeliveryStatusPending,
+			"attempt_count":   0,
+			"next_attempt_at": time.Now(),
+		})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return nil, ErrWebhookDeliveryNotFound
+	}
+
+	return s.GetDelivery(subscriptionID, deliveryID)
+}
+
+// EnqueueDeliveries creates a pending delivery of an event for every active
+// subscription that wants it. Only commerce events are delivered, even to "*"
+// subscriptions. Handling the same event twice is a no-op.
+func (s *WebhookService) EnqueueDeliveries(envelope *schema.Envelope) error {
+	if !schema.IsWebhookEventType(envelope.EventType) {
+		return nil
+	}
+
+	var subscriptions []models.WebhookSubscription
+	if err := s.db.Where("is_active = ?", true).Find(&subscriptions).Error; err != nil {
+		return err
+	}
+
+	payload, err := json.Marshal(envelope)
+	if err != nil {
+		return err
+	}
+
+	var deliveries []models.WebhookDelivery
+	for i := range subscriptions {
+		if !subscriptions[i].Subscribes(envelope.EventType) {
+			continue
+		}
+
+		deliveries = append(deliveries, models.WebhookDelivery{
+			SubscriptionID: subscriptions[i].ID,
+			EventID:        envelope.EventID,
+			EventType:      envelope.EventType,
+			Payload:        string(payload),
+			Status:         models.WebhookDeliveryStatusPending,
+			NextAttemptAt:  time.Now(),
+		})
+	}
+
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	return s.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&deliveries).Error
+}
+
+// StartDeliveryWorker sends due deliveries every delivery interval until ctx is cancelled.
+func (s *WebhookService) StartDeliveryWorker(ctx context.Context, logger *zerolog.Logger) {
+	ticker := time.NewTicker(s.config.DeliveryInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			delivered, err := s.DeliverPending(ctx)
+			if err != nil {
+				logger.Error().Err(err).Msg("failed to deliver webhooks")
+				continue
+			}
+
+			if delivered > 0 {
+				logger.Info().Int("deliveries", delivered).Msg("attempted webhook deliveries")
+			}
+		}
+	}
+}
+
+// DeliverPending attempts one batch of due deliveries and returns how many were attempted.
+func (s *WebhookService) DeliverPending(ctx context.Context) (int, error) {
+	var due []models.WebhookDelivery
+
+	// Claim the batch by pushing next_attempt_at past the request timeout, so
+	// no row lock is held during HTTP calls and other workers skip these rows
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", models.WebhookDeliveryStatusPending, time.Now()).
+			Order("next_attempt_at ASC, id ASC").
+			Limit(s.config.BatchSize).
+			Find(&due).Error; err != nil {
+			return err
+		}
+
+		if len(due) == 0 {
+			return nil
+		}
+
+		ids := make([]uint64, len(due))
+		for i := range due {
+			ids[i] = due[i].ID
+		}
+
+		return tx.Model(&models.WebhookDelivery{}).
+			Where("id IN ?", ids).
+			Update("next_attempt_at", time.Now().Add(2*s.config.Timeout)).Error
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	for i := range due {
+		if ctx.Err() != nil {
+			break
+		}
+
+		if err := s.deliver(ctx, &due[i]); err != nil {
+			return i, err
+		}
+	}
+
+	return len(due), nil
+}
+
+func (s *WebhookService) deliver(ctx context.Context, delivery *models.WebhookDelivery) error {
+	var subscription models.WebhookSubscription
+	err := s.db.First(&subscription, delivery.SubscriptionID).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	// Subscriptions deleted or disabled after the event was queued are not called
+	if err != nil || !subscription.IsActive {
+		return s.db.Model(delivery).Updates(map[string]any{
+			"status":     models.WebhookDeliveryStatusFailed,
+			"last_error": "subscription is no longer active",
+		}).Error
+	}
+
+	attempt := s.send(ctx, &subscription, delivery)
+	attempt.DeliveryID = delivery.ID
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&attempt).Error; err != nil {
+			return err
+		}
+
+		attemptCount := delivery.AttemptCount + 1
+		updates := map[string]any{
+			"attempt_count":    attemptCount,
+			"last_status_code": attempt.StatusCode,
+			"last_error":       attempt.Error,
+		}
+
+		switch {
+		case attempt.Error == "":
+			updates["status"] = models.WebhookDeliveryStatusSucceeded
+			updates["delivered_at"] = time.Now()
+		case attemptCount >= s.config.MaxAttempts:
+			updates["status"] = models.WebhookDeliveryStatusFailed
+		default:
+			updates["next_attempt_at"] = time.Now().Add(utils.Backoff(attemptCount, s.config.RetryBaseDelay, s.config.RetryMaxDelay))
+		}
+
+		return tx.Model(delivery).Updates(updates).Error
+	})
+}
+
+// send POSTs the event to the subscriber. The signature covers the timestamp
+// and the body, "<timestamp>.<body>", so a captured request cannot be replayed later.
+func (s *WebhookService) send(ctx context.Context, subscription *models.WebhookSubscription, delivery *models.WebhookDelivery) models.WebhookDeliveryAttempt {
+	var attempt models.WebhookDeliveryAttempt
+
+	body := []byte(delivery.Payload)
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	signature := utils.SignPayload(subscription.Secret, append([]byte(timestamp+"."), body...))
+
+	ctx, cancel := context.WithTimeout(ctx, s.config.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.URL, bytes.NewReader(body))
+	if err != nil {
+		attempt.Error = err.Error()
+		return attempt
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", webhookUserAgent)
+	req.Header.Set("X-Webhook-ID", strconv.FormatUint(delivery.ID, 10))
+	req.Header.Set("X-Webhook-Event", delivery.EventType)
+	req.Header.Set("X-Webhook-Timestamp", timestamp)
+	req.Header.Set("X-Webhook-Signature", "sha256="+signature)
+
+	started := time.Now()
+	resp, err := s.httpClient.Do(req)
+	attempt.DurationMs = int(time.Since(started).Milliseconds())
+	if err != nil {
+		attempt.Error = err.Error()
+		return attempt
+	}
+	defer resp.Body.Close()
+
+	responseBody, _ := io.ReadAll(io.LimitReader(resp.Body, maxWebhookResponseBody))
+	attempt.StatusCode = &resp.StatusCode
+	attempt.ResponseBody = string(responseBody)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		attempt.Error = fmt.Sprintf("receiver responded with status %d", resp.StatusCode)
+	}
+
+	return attempt
+}
+
+func (s *WebhookService) findSubscription(db *gorm.DB, id uint) (*models.WebhookSubscription, error) {
+	var subscription models.WebhookSubscription
+	if err := db.First(&subscription, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrWebhookSubscriptionNotFound
+		}
+		return nil, err
+	}
+
+	return &subscription, nil
+}
+
+func (s *WebhookService) convertToSubscriptionResponse(subscription *models.WebhookSubscription) dto.WebhookSubscriptionResponse {
+	return dto.WebhookSubscriptionResponse{
+		ID:          subscription.ID,
+		URL:         subscription.URL,
+		EventTypes:  subscription.EventTypes,
+		Description: subscription.Description,
+		IsActive:    subscription.IsActive,
+		CreatedAt:   subscription.CreatedAt,
+		UpdatedAt:   subscription.UpdatedAt,
+	}
+}
+
+func (s *WebhookService) convertToDeliveryResponse(delivery *models.WebhookDelivery) dto.WebhookDeliveryResponse {
+	attempts := make([]dto.WebhookDeliveryAttemptResponse, len(delivery.Attempts))
+	for i := range delivery.Attempts {
+		attempts[i] = dto.WebhookDeliveryAttemptResponse{
+			ID:           delivery.Attempts[i].ID,
+			StatusCode:   delivery.Attempts[i].StatusCode,
+			Error:        delivery.Attempts[i].Error,
+			ResponseBody: delivery.Attempts[i].ResponseBody,
+			DurationMs:   delivery.Attempts[i].DurationMs,
+			CreatedAt:    delivery.Attempts[i].CreatedAt,
+		}
+	}
+
+	return dto.WebhookDeliveryResponse{
+		ID:             delivery.ID,
+		SubscriptionID: delivery.SubscriptionID,
+		EventID:        delivery.EventID,
+		EventType:      delivery.EventType,
+		Status:         string(delivery.Status),
+		AttemptCount:   delivery.AttemptCount,
+		LastStatusCode: delivery.LastStatusCode,
+		LastError:      delivery.LastError,
+		NextAttemptAt:  delivery.NextAttemptAt,
+		DeliveredAt:    delivery.DeliveredAt,
+		CreatedAt:      delivery.CreatedAt,
+		Attempts:       attempts,
+	}
+}
+
+func validateEventTypes(eventTypes []string) error {
+	for _, eventType := range eventTypes {
+		if eventType == models.WebhookEventTypeAll {
+			continue
+		}
+
+		if !schema.IsWebhookEventType(eventType) {
+			return fmt.Errorf("%w: %s", ErrUnknownEventType, eventType)
+		}
+	}
+	return nil
+}
+
+func generateWebhookSecret() (string, error) {
+	token, err := utils.GenerateToken(32)
+	if err != nil {
+		return "", err
+	}
+	return "whsec_" + token, nil
+}
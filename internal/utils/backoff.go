@@ -0,0 +1,17 @@
+package utils
+
+import "time"
+
+// Backoff returns the delay before retry number attempt (starting at 1),
+// doubling base with every attempt up to max.
+func Backoff(attempt int, base, max time.Duration) time.Duration {
+	delay := base
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if delay >= max {
+			return max
+		}
+	}
+
+	return min(delay, max)
+}
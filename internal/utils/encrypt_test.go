@@ -0,0 +1,58 @@
+package utils
+
+import (
+	"encoding/base64"
+	"testing"
+)
+
+func TestEncryptDecrypt(t *testing.T) {
+	ciphertext, err := Encrypt("key", "JBSWY3DPEHPK3PXP")
+	if err != nil {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+
+	plaintext, err := Decrypt("key", ciphertext)
+	if err != nil {
+		t.Fatalf("Decrypt() error = %v", err)
+	}
+
+	if plaintext != "JBSWY3DPEHPK3PXP" {
+		t.Errorf("Decrypt() = %q, want %q", plaintext, "JBSWY3DPEHPK3PXP")
+	}
+}
+
+func TestEncryptUsesRandomNonce(t *testing.T) {
+	first, _ := Encrypt("key", "secret")
+	second, _ := Encrypt("key", "secret")
+
+	if first == second {
+		t.Error("Encrypt() returned the same ciphertext twice")
+	}
+}
+
+func TestDecryptRejectsWrongKey(t *testing.T) {
+	ciphertext, _ := Encrypt("key", "secret")
+
+	if _, err := Decrypt("other", ciphertext); err == nil {
+		t.Error("Decrypt() with the wrong key succeeded")
+	}
+}
+
+func TestDecryptRejectsTamperedCiphertext(t *testing.T) {
+	ciphertext, _ := Encrypt("key", "secret")
+
+	sealed, _ := base64.StdEncoding.DecodeString(ciphertext)
+	sealed[len(sealed)-1] ^= 0xff
+
+	if _, err := Decrypt("key", base64.StdEncoding.EncodeToString(sealed)); err == nil {
+		t.Error("Decrypt() of a tampered ciphertext succeeded")
+	}
+}
+
+func TestDecryptRejectsMalformedCiphertext(t *testing.T) {
+	for _, ciphertext := range []string{"not base64!", base64.StdEncoding.EncodeToString([]byte("short"))} {
+		if _, err := Decrypt("key", ciphertext); err == nil {
+			t.Errorf("Decrypt(%q) succeeded", ciphertext)
+		}
+	}
+}
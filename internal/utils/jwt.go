@@ -5,69 +5,115 @@ import (
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 	"github.com/tomimandalaputra/e-commerce-go/internal/config"
 )
 
+// Token types, carried in the token_type claim so one kind of token is never accepted as the other
+const (
+	TokenTypeAccess  = "access"
+	TokenTypeRefresh = "refresh"
+)
+
 // Claims contains the data for the user
 type Claims struct {
-	UserID uint   `json:"user_id"`
-	Email  string `json:"email"`
-	Role   string `json:"role"`
+	UserID    uint   `json:"user_id"`
+	Email     string `json:"email"`
+	TokenType string `json:"token_type"`
 	jwt.RegisteredClaims
 }
 
-// GenerateTokenPair generates access and refresh token
-func GenerateTokenPair(cfg *config.JWTConfig, userID uint, email, role string) (accessToken, refreshToken string, err error) {
+// TokenPair is an issued access and refresh token. The access token ID is its
+// jti claim, which is what gets revoked
+type TokenPair struct {
+	AccessToken          string
+	AccessTokenID        string
+	AccessTokenExpiresAt time.Time
+	RefreshToken         string
+}
+
+// GenerateTokenPair generates access and refresh token signed with the signing key of keys
+func GenerateTokenPair(cfg *config.JWTConfig, keys *KeySet, userID uint, email string) (*TokenPair, error) {
 
 	// Access token
+	accessTokenID := uuid.NewString()
+	accessTokenExpiresAt := time.Now().Add(cfg.ExpiresIn)
 	accessClaims := &Claims{
-		UserID: userID,
-		Email:  email,
-		Role:   role,
+		UserID:    userID,
+		Email:     email,
+		TokenType: TokenTypeAccess,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(cfg.ExpiresIn)),
+			Issuer:    cfg.Issuer,
+			Audience:  jwt.ClaimStrings{audience(cfg, TokenTypeAccess)},
+			ID:        accessTokenID,
+			ExpiresAt: jwt.NewNumericDate(accessTokenExpiresAt),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 		},
 	}
 
-	at := jwt.NewWithClaims(jwt.SigningMethodHS256, accessClaims)
-	accessTokenString, err := at.SignedString([]byte(cfg.Secret))
+	accessTokenString, err := keys.sign(accessClaims)
 	if err != nil {
-		return "", "", err
+		return nil, err
 	}
 
-	// Refresh token
+	// Refresh token, with a unique ID so two issued in the same second never collide
 	refreshClaims := &Claims{
-		UserID: userID,
-		Email:  email,
-		Role:   role,
+		UserID:    userID,
+		Email:     email,
+		TokenType: TokenTypeRefresh,
 		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    cfg.Issuer,
+			Audience:  jwt.ClaimStrings{audience(cfg, TokenTypeRefresh)},
+			ID:        uuid.NewString(),
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(cfg.RefreshTokenExpires)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 		},
 	}
-	rt := jwt.NewWithClaims(jwt.SigningMethodHS256, refreshClaims)
-	refreshTokenString, err := rt.SignedString([]byte(cfg.Secret))
+	refreshTokenString, err := keys.sign(refreshClaims)
 	if err != nil {
-		return "", "", err
+		return nil, err
 	}
 
-	return accessTokenString, refreshTokenString, nil
+	return &TokenPair{
+		AccessToken:          accessTokenString,
+		AccessTokenID:        accessTokenID,
+		AccessTokenExpiresAt: accessTokenExpiresAt,
+		RefreshToken:         refreshTokenString,
+	}, nil
 }
 
-// ValidateToken checks if jwt token is valid
-func ValidateToken(tokenString, secret string) (*Claims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (any, error) {
-		return []byte(secret), nil
-	})
+// ValidateToken checks if jwt token is valid: signed by one of the verification
+// keys with the algorithm of that key, issued by us for the expected audience,
+// not expired and of the expected token type
+func ValidateToken(cfg *config.JWTConfig, keys *KeySet, tokenString, tokenType string) (*Claims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, keys.keyfunc,
+		jwt.WithValidMethods(keys.methods()),
+		jwt.WithIssuer(cfg.Issuer),
+		jwt.WithAudience(audience(cfg, tokenType)),
+		jwt.WithExpirationRequired(),
+	)
 
 	if err != nil {
 		return nil, err
 	}
 
-	if claims, ok := token.Claims.(*Claims); ok && token.Valid {
-		return claims, nil
+	claims, ok := token.Claims.(*Claims)
+	if !ok || !token.Valid {
+		return nil, errors.New("invalid token")
 	}
 
-	return nil, errors.New("invalid token")
+	if claims.TokenType != tokenType {
+		return nil, errors.New("unexpected token type")
+	}
+
+	return claims, nil
+}
+
+// audience returns who a token type is meant for. Refresh tokens are only ever
+// redeemed by this API, so services that accept our access tokens never accept them
+func audience(cfg *config.JWTConfig, tokenType string) string {
+	if tokenType == TokenTypeRefresh {
+		return cfg.Issuer
+	}
+	return cfg.Audience
 }
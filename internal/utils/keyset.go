@@ -0,0 +1,220 @@
+package utils
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/tomimandalaputra/e-commerce-go/internal/config"
+)
+
+// minRSAKeyBits is the smallest RSA key accepted for signing or verification
+const minRSAKeyBits = 2048
+
+// KeySet holds the key tokens are signed with and every key they are
+// verified against. With no signing key file configured it falls back to
+// HS256 and the shared JWT secret.
+//
+// Asymmetric keys are identified by their RFC 7638 thumbprint, sent as the kid
+// header. To rotate, add the new public key as a verification key everywhere,
+// then make it the signing key and keep the old public key until the tokens
+// it signed have expired.
+type KeySet struct {
+	method       jwt.SigningMethod
+	signingKey   any
+	signingKeyID string
+	keys         map[string]verificationKey
+}
+
+type verificationKey struct {
+	method jwt.SigningMethod
+	key    crypto.PublicKey
+	jwk    *JWK
+}
+
+// JWK is a public key in JSON Web Key format
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	// RSA
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+	// Ed25519
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+// JWKS is a JSON Web Key Set
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// NewKeySet loads the signing and verification keys from cfg
+func NewKeySet(cfg *config.JWTConfig) (*KeySet, error) {
+	if cfg.SigningKeyFile == "" {
+		if cfg.Secret == "" {
+			return nil, errors.New("a JWT secret is required to sign with HS256")
+		}
+
+		return &KeySet{
+			method:     jwt.SigningMethodHS256,
+			signingKey: []byte(cfg.Secret),
+		}, nil
+	}
+
+	data, err := os.ReadFile(cfg.SigningKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read JWT signing key: %w", err)
+	}
+
+	keySet := &KeySet{keys: make(map[string]verificationKey)}
+
+	var publicKey crypto.PublicKey
+	if rsaKey, err := jwt.ParseRSAPrivateKeyFromPEM(data); err == nil {
+		keySet.method = jwt.SigningMethodRS256
+		keySet.signingKey = rsaKey
+		publicKey = &rsaKey.PublicKey
+	} else if edKey, err := jwt.ParseEdPrivateKeyFromPEM(data); err == nil {
+		keySet.method = jwt.SigningMethodEdDSA
+		keySet.signingKey = edKey
+		publicKey = edKey.(ed25519.PrivateKey).Public()
+	} else {
+		return nil, fmt.Errorf("JWT signing key %s is not an RSA or Ed25519 private key", cfg.SigningKeyFile)
+	}
+
+	keySet.signingKeyID, err = keySet.add(publicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, path := range cfg.VerificationKeyFiles {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read JWT verification key: %w", err)
+		}
+
+		var publicKey crypto.PublicKey
+		if rsaKey, err := jwt.ParseRSAPublicKeyFromPEM(data); err == nil {
+			publicKey = rsaKey
+		} else if edKey, err := jwt.ParseEdPublicKeyFromPEM(data); err == nil {
+			publicKey = edKey
+		} else {
+			return nil, fmt.Errorf("JWT verification key %s is not an RSA or Ed25519 public key", path)
+		}
+
+		if _, err := keySet.add(publicKey); err != nil {
+			return nil, err
+		}
+	}
+
+	return keySet, nil
+}
+
+// add registers a verification key and returns its kid
+func (k *KeySet) add(publicKey crypto.PublicKey) (string, error) {
+	jwk, err := toJWK(publicKey)
+	if err != nil {
+		return "", err
+	}
+
+	k.keys[jwk.Kid] = verificationKey{method: jwt.GetSigningMethod(jwk.Alg), key: publicKey, jwk: jwk}
+	return jwk.Kid, nil
+}
+
+// JWKS returns the public verification keys. It is empty for HS256, whose
+// secret must never be published
+func (k *KeySet) JWKS() *JWKS {
+	jwks := &JWKS{Keys: []JWK{}}
+	for _, key := range k.keys {
+		jwks.Keys = append(jwks.Keys, *key.jwk)
+	}
+
+	// Map order is random; keep the response stable
+	slices.SortFunc(jwks.Keys, func(a, b JWK) int { return strings.Compare(a.Kid, b.Kid) })
+	return jwks
+}
+
+// methods lists the algorithms tokens may be signed with
+func (k *KeySet) methods() []string {
+	if k.keys == nil {
+		return []string{jwt.SigningMethodHS256.Alg()}
+	}
+
+	var methods []string
+	for _, key := range k.keys {
+		if !slices.Contains(methods, key.method.Alg()) {
+			methods = append(methods, key.method.Alg())
+		}
+	}
+	return methods
+}
+
+// sign signs claims with the signing key, setting the kid header for asymmetric keys
+func (k *KeySet) sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(k.method, claims)
+	if k.signingKeyID != "" {
+		token.Header["kid"] = k.signingKeyID
+	}
+	return token.SignedString(k.signingKey)
+}
+
+// keyfunc picks the verification key named by the kid header and refuses any
+// algorithm other than the one that key was registered for
+func (k *KeySet) keyfunc(token *jwt.Token) (any, error) {
+	if k.keys == nil {
+		if token.Method != jwt.SigningMethodHS256 {
+			return nil, fmt.Errorf("unexpected signing method %s", token.Method.Alg())
+		}
+		return k.signingKey, nil
+	}
+
+	kid, _ := token.Header["kid"].(string)
+	key, ok := k.keys[kid]
+	if !ok {
+		return nil, errors.New("unknown signing key")
+	}
+
+	if token.Method.Alg() != key.method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method %s", token.Method.Alg())
+	}
+
+	return key.key, nil
+}
+
+// toJWK converts a public key to a JWK whose kid is its RFC 7638 thumbprint
+func toJWK(publicKey crypto.PublicKey) (*JWK, error) {
+	encode := base64.RawURLEncoding.EncodeToString
+
+	var jwk JWK
+	var members string
+	switch key := publicKey.(type) {
+	case *rsa.PublicKey:
+		if key.N.BitLen() < minRSAKeyBits {
+			return nil, fmt.Errorf("RSA keys must be at least %d bits", minRSAKeyBits)
+		}
+
+		jwk = JWK{Kty: "RSA", Alg: jwt.SigningMethodRS256.Alg(), N: encode(key.N.Bytes()), E: encode(big.NewInt(int64(key.E)).Bytes())}
+		members = fmt.Sprintf(`{"e":%q,"kty":"RSA","n":%q}`, jwk.E, jwk.N)
+	case ed25519.PublicKey:
+		jwk = JWK{Kty: "OKP", Alg: jwt.SigningMethodEdDSA.Alg(), Crv: "Ed25519", X: encode(key)}
+		members = fmt.Sprintf(`{"crv":"Ed25519","kty":"OKP","x":%q}`, jwk.X)
+	default:
+		return nil, fmt.Errorf("unsupported key type %T", publicKey)
+	}
+
+	thumbprint := sha256.Sum256([]byte(members))
+	jwk.Kid = encode(thumbprint[:])
+	jwk.Use = "sig"
+	return &jwk, nil
+}
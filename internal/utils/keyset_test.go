@@ -0,0 +1,235 @@
+package utils
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/tomimandalaputra/e-commerce-go/internal/config"
+)
+
+func TestToJWKThumbprint(t *testing.T) {
+	decode := base64.RawURLEncoding.DecodeString
+
+	// RFC 7638 section 3.1
+	n, _ := decode("0vx7agoebGcQSuuPiLJXZptN9nndrQmbXEps2aiAFbWhM78LhWx4cbbfAAtVT86zwu1RK7aPFFxuhDR1L6tSoc_BJECPebWKRXjBZCiFV4n3oknjhMstn64tZ_2W-5JsGY4Hc5n9yBXArwl93lqt7_RN5w6Cf0h4QyQ5v-65YGjQR0_FDW2QvzqY368QQMicAtaSqzs8KJZgnYb9c7d0zgdAZHzu6qMQvRL5hajrn1n91CbOpbISD08qNLyrdkt-bFTWhAI4vMQFh6WeZu0fM4lFd2NcRwr3XPksINHaQ-G_xBniIqbw0Ls1jF44-csFCur-kEgU8awapJzKnqDKgw")
+	rsaKey := &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: 65537}
+
+	// RFC 8037 appendix A.3
+	x, _ := decode("11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo")
+	edKey := ed25519.PublicKey(x)
+
+	tests := []struct {
+		name string
+		key  crypto.PublicKey
+		kid  string
+		alg  string
+	}{
+		{"RSA", rsaKey, "NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs", "RS256"},
+		{"Ed25519", edKey, "kPrK_qmxVWaYVA9wwBF6Iuo3vVzz7TxHCTwXBygrS4k", "EdDSA"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jwk, err := toJWK(tt.key)
+			if err != nil {
+				t.Fatalf("toJWK() error = %v", err)
+			}
+
+			if jwk.Kid != tt.kid {
+				t.Errorf("kid = %s, want %s", jwk.Kid, tt.kid)
+			}
+
+			if jwk.Alg != tt.alg || jwk.Use != "sig" {
+				t.Errorf("alg, use = %s, %s, want %s, sig", jwk.Alg, jwk.Use, tt.alg)
+			}
+		})
+	}
+}
+
+func TestToJWKRejectsSmallRSAKeys(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := toJWK(&key.PublicKey); err == nil {
+		t.Error("toJWK() accepted a 1024 bit RSA key")
+	}
+}
+
+func TestKeySetHS256RequiresSecret(t *testing.T) {
+	cfg := testJWTConfig()
+	cfg.Secret = ""
+
+	if _, err := NewKeySet(cfg); err == nil {
+		t.Error("NewKeySet() accepted HS256 without a secret")
+	}
+}
+
+func TestKeySetHS256Fallback(t *testing.T) {
+	cfg := testJWTConfig()
+
+	keys, err := NewKeySet(cfg)
+	if err != nil {
+		t.Fatalf("NewKeySet() error = %v", err)
+	}
+
+	if jwks := keys.JWKS(); len(jwks.Keys) != 0 {
+		t.Errorf("JWKS() published %d keys for HS256, want none", len(jwks.Keys))
+	}
+
+	pair, err := GenerateTokenPair(cfg, keys, 1, "jane@example.com")
+	if err != nil {
+		t.Fatalf("GenerateTokenPair() error = %v", err)
+	}
+
+	if _, err := ValidateToken(cfg, keys, pair.AccessToken, TokenTypeAccess); err != nil {
+		t.Errorf("ValidateToken() error = %v", err)
+	}
+}
+
+func TestKeySetRotation(t *testing.T) {
+	dir := t.TempDir()
+	oldPrivate, oldPublic := writeEd25519Key(t, dir, "old")
+	newPrivate, _ := writeEd25519Key(t, dir, "new")
+
+	oldCfg := testJWTConfig()
+	oldCfg.SigningKeyFile = oldPrivate
+	oldKeys, err := NewKeySet(oldCfg)
+	if err != nil {
+		t.Fatalf("NewKeySet() error = %v", err)
+	}
+
+	oldToken, err := GenerateTokenPair(oldCfg, oldKeys, 1, "jane@example.com")
+	if err != nil {
+		t.Fatalf("GenerateTokenPair() error = %v", err)
+	}
+
+	// Signing with the new key while still accepting the old one
+	rotatedCfg := testJWTConfig()
+	rotatedCfg.SigningKeyFile = newPrivate
+	rotatedCfg.VerificationKeyFiles = []string{oldPublic}
+	rotatedKeys, err := NewKeySet(rotatedCfg)
+	if err != nil {
+		t.Fatalf("NewKeySet() error = %v", err)
+	}
+
+	if jwks := rotatedKeys.JWKS(); len(jwks.Keys) != 2 {
+		t.Errorf("JWKS() published %d keys, want 2", len(jwks.Keys))
+	}
+
+	newToken, err := GenerateTokenPair(rotatedCfg, rotatedKeys, 1, "jane@example.com")
+	if err != nil {
+		t.Fatalf("GenerateTokenPair() error = %v", err)
+	}
+
+	for name, token := range map[string]string{"old": oldToken.AccessToken, "new": newToken.AccessToken} {
+		if _, err := ValidateToken(rotatedCfg, rotatedKeys, token, TokenTypeAccess); err != nil {
+			t.Errorf("ValidateToken(%s token) error = %v", name, err)
+		}
+	}
+
+	// Once the old key is dropped its tokens are refused
+	newOnlyCfg := testJWTConfig()
+	newOnlyCfg.SigningKeyFile = newPrivate
+	newOnlyKeys, err := NewKeySet(newOnlyCfg)
+	if err != nil {
+		t.Fatalf("NewKeySet() error = %v", err)
+	}
+
+	if _, err := ValidateToken(newOnlyCfg, newOnlyKeys, oldToken.AccessToken, TokenTypeAccess); err == nil {
+		t.Error("ValidateToken() accepted a token signed by a dropped key")
+	}
+}
+
+func TestKeySetRejectsAlgorithmConfusion(t *testing.T) {
+	dir := t.TempDir()
+	private, public := writeEd25519Key(t, dir, "signing")
+
+	cfg := testJWTConfig()
+	cfg.SigningKeyFile = private
+	keys, err := NewKeySet(cfg)
+	if err != nil {
+		t.Fatalf("NewKeySet() error = %v", err)
+	}
+
+	// An HS256 token keyed with the published public key under its kid
+	publicPEM, err := os.ReadFile(public)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, &Claims{
+		UserID:    1,
+		TokenType: TokenTypeAccess,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    cfg.Issuer,
+			Audience:  jwt.ClaimStrings{cfg.Audience},
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+	})
+	token.Header["kid"] = keys.signingKeyID
+
+	forged, err := token.SignedString(publicPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := ValidateToken(cfg, keys, forged, TokenTypeAccess); err == nil {
+		t.Error("ValidateToken() accepted an HS256 token against an EdDSA key")
+	}
+}
+
+func testJWTConfig() *config.JWTConfig {
+	return &config.JWTConfig{
+		Secret:              "test-secret",
+		ExpiresIn:           time.Hour,
+		RefreshTokenExpires: 24 * time.Hour,
+		Issuer:              "e-commerce-api",
+		Audience:            "e-commerce",
+	}
+}
+
+// writeEd25519Key writes a new Ed25519 key pair as PEM files and returns their paths
+func writeEd25519Key(t *testing.T, dir, name string) (string, string) {
+	t.Helper()
+
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	privateDER, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	publicDER, err := x509.MarshalPKIXPublicKey(public)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	privatePath := filepath.Join(dir, name+".pem")
+	publicPath := filepath.Join(dir, name+".pub.pem")
+
+	if err := os.WriteFile(privatePath, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateDER}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(publicPath, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	return privatePath, publicPath
+}
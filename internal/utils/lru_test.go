@@ -0,0 +1,61 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLRUGetSet(t *testing.T) {
+	cache := NewLRU[string, int](2)
+
+	if _, ok := cache.Get("a"); ok {
+		t.Fatal("Get() on empty cache found a value")
+	}
+
+	cache.Set("a", 1, time.Minute)
+	cache.Set("a", 2, time.Minute)
+
+	if got, ok := cache.Get("a"); !ok || got != 2 {
+		t.Errorf("Get(a) = %d, %v, want 2, true", got, ok)
+	}
+}
+
+func TestLRUEvictsLeastRecentlyUsed(t *testing.T) {
+	cache := NewLRU[string, int](2)
+	cache.Set("a", 1, time.Minute)
+	cache.Set("b", 2, time.Minute)
+
+	// Reading a makes b the least recently used entry
+	cache.Get("a")
+	cache.Set("c", 3, time.Minute)
+
+	if _, ok := cache.Get("b"); ok {
+		t.Error("b was not evicted")
+	}
+
+	for key, want := range map[string]int{"a": 1, "c": 3} {
+		if got, ok := cache.Get(key); !ok || got != want {
+			t.Errorf("Get(%s) = %d, %v, want %d, true", key, got, ok, want)
+		}
+	}
+}
+
+func TestLRUExpires(t *testing.T) {
+	cache := NewLRU[string, int](2)
+	cache.Set("a", 1, -time.Second)
+
+	if _, ok := cache.Get("a"); ok {
+		t.Error("Get() returned an expired entry")
+	}
+}
+
+func TestLRUDelete(t *testing.T) {
+	cache := NewLRU[string, int](2)
+	cache.Set("a", 1, time.Minute)
+	cache.Delete("a")
+	cache.Delete("missing")
+
+	if _, ok := cache.Get("a"); ok {
+		t.Error("Get() returned a deleted entry")
+	}
+}
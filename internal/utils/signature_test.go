@@ -0,0 +1,42 @@
+package utils
+
+import "testing"
+
+func TestSignPayload(t *testing.T) {
+	// RFC 4231 test case 2
+	got := SignPayload("Jefe", []byte("what do ya want for nothing?"))
+	want := "5bdcc146bf60754e6a042426089575c75a003f089d2739839dec58b964ec3843"
+	if got != want {
+		t.Errorf("SignPayload() = %s, want %s", got, want)
+	}
+}
+
+func TestVerifySignature(t *testing.T) {
+	secret := "whsec_test"
+	payload := []byte(`{"id":"evt_1","type":"payment.succeeded"}`)
+	signature := SignPayload(secret, payload)
+
+	tests := []struct {
+		name      string
+		secret    string
+		payload   []byte
+		signature string
+		want      bool
+	}{
+		{"valid", secret, payload, signature, true},
+		{"valid with prefix", secret, payload, "sha256=" + signature, true},
+		{"wrong secret", "other", payload, signature, false},
+		{"tampered payload", secret, []byte(`{"id":"evt_2","type":"payment.succeeded"}`), signature, false},
+		{"truncated signature", secret, payload, signature[:len(signature)-2], false},
+		{"not hex", secret, payload, "sha256=not-hex", false},
+		{"empty", secret, payload, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := VerifySignature(tt.secret, tt.payload, tt.signature); got != tt.want {
+				t.Errorf("VerifySignature() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
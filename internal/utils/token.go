@@ -0,0 +1,28 @@
+package utils
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// GenerateToken returns a random hex encoded token made of size random bytes
+func GenerateToken(size int) (string, error) {
+	buf := make([]byte, size)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// HashRefreshToken returns the SHA-256 a refresh token is stored under. Refresh
+// tokens are signed JWTs and already unguessable, so no key is needed
+func HashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// HashToken returns the keyed hash a token is stored under, so the plain token never reaches the database
+func HashToken(secret, token string) string {
+	return SignPayload(secret, []byte(token))
+}